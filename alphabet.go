@@ -0,0 +1,332 @@
+package morse
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Alphabet is a Dictionary loaded from a definition file, along
+// with its metadata. See LoadAlphabet
+type Alphabet struct {
+	// Name is the human-readable name of the alphabet, e.g. "Russian"
+	Name string
+	// Language is the language code of the alphabet, e.g. "ru"
+	Language string
+	// ShiftIn and ShiftOut are the prosigns sent to shift into and out of
+	// the alphabet from another (e.g. Wabun's DO and SN), or nil if none
+	ShiftIn, ShiftOut Code
+	// Dictionary contains the characters of the alphabet
+	Dictionary *Dictionary
+}
+
+// Shift returns the Shift into the alphabet, for use with Dictionary.AddShift.
+// Returns false if the alphabet doesn't have shift prosigns
+func (a *Alphabet) Shift() (Shift, bool) {
+	if a.ShiftIn == nil {
+		return Shift{}, false
+	}
+	return Shift{Dictionary: a.Dictionary, In: a.ShiftIn, Out: a.ShiftOut}, true
+}
+
+// AlphabetError is returned by LoadAlphabet when the definition is invalid
+type AlphabetError struct {
+	// Line is the (1-based) line of the definition the error is on
+	Line int
+	Err  error
+}
+
+func (e *AlphabetError) Error() string {
+	return fmt.Sprintf("morse: alphabet line %d: %v", e.Line, e.Err)
+}
+
+func (e *AlphabetError) Unwrap() error {
+	return e.Err
+}
+
+// alphabetEntry is a single character in an alphabet definition
+type alphabetEntry struct {
+	Text       string `json:"text"`
+	Code       string `json:"code"`
+	EncodeOnly bool   `json:"encodeOnly"`
+	line       int
+}
+
+// alphabetFile is the JSON alphabet definition
+type alphabetFile struct {
+	Name       string          `json:"name"`
+	Language   string          `json:"language"`
+	ShiftIn    string          `json:"shiftIn"`
+	ShiftOut   string          `json:"shiftOut"`
+	Characters []alphabetEntry `json:"characters"`
+}
+
+// LoadAlphabet parses an alphabet definition file in JSON or CSV format (detected
+// from the first non-whitespace character), and returns the Alphabet. If the
+// definition is invalid, an *AlphabetError is returned with the line of the error.
+//
+// The JSON format is an object with the (optional) metadata and a list of characters:
+//
+//	{
+//	  "name": "Russian",
+//	  "language": "ru",
+//	  "characters": [
+//	    {"text": "ё", "code": "・", "encodeOnly": true},
+//	    {"text": "е", "code": "・"},
+//	    ...
+//	  ]
+//	}
+//
+// The CSV format has a row for each character, with the text, the code and
+// optionally "encode-only". Rows that start with @ followed by a name (e.g.
+// "@language,ja") are metadata, and lines starting with # are comments:
+//
+//	# Wabun
+//	@name,Wabun
+//	@shiftIn,－・・－－－
+//	@shiftOut,・・・－・
+//	イ,・－
+//	ロ,・－・－
+//
+// The text of a character may be more than one rune (see Dictionary.AddText).
+// Codes may use '・' or '.' for dits and '－' or '-' for dahs, and there must only
+// be one character (that isn't encode-only) for each code. The shift metadata
+// is either both given or neither
+func LoadAlphabet(r io.Reader) (*Alphabet, error) {
+	br := bufio.NewReader(r)
+	// The leading whitespace is given back to the parser, so the lines of errors are right
+	leading := bytes.Buffer{}
+	for {
+		c, _, err := br.ReadRune()
+		if err == io.EOF {
+			return nil, &AlphabetError{Line: 1, Err: errors.New("empty definition")}
+		} else if err != nil {
+			return nil, err
+		}
+		if !unicode.IsSpace(c) {
+			_ = br.UnreadRune()
+			def := io.MultiReader(&leading, br)
+			if c == '{' {
+				return loadAlphabetJSON(def)
+			}
+			return loadAlphabetCSV(def)
+		}
+		leading.WriteRune(c)
+	}
+}
+
+func loadAlphabetJSON(r io.Reader) (*Alphabet, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	lineAt := func(offset int64) int {
+		return bytes.Count(data[:offset], []byte("\n")) + 1
+	}
+
+	f := alphabetFile{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	jsonErr := func(err error) error {
+		var syntaxErr *json.SyntaxError
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &syntaxErr) {
+			return &AlphabetError{Line: lineAt(syntaxErr.Offset), Err: err}
+		} else if errors.As(err, &typeErr) {
+			return &AlphabetError{Line: lineAt(typeErr.Offset), Err: err}
+		} else if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return &AlphabetError{Line: lineAt(int64(len(data))), Err: io.ErrUnexpectedEOF}
+		}
+		return &AlphabetError{Line: lineAt(dec.InputOffset()), Err: err}
+	}
+
+	// Stream the tokens, so the line of each character is known
+	if _, err = dec.Token(); err != nil {
+		return nil, jsonErr(err)
+	}
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return nil, jsonErr(err)
+		}
+		key, _ := t.(string)
+		var value interface{}
+		switch key {
+		case "name":
+			value = &f.Name
+		case "language":
+			value = &f.Language
+		case "shiftIn":
+			value = &f.ShiftIn
+		case "shiftOut":
+			value = &f.ShiftOut
+		case "characters":
+			if t, err = dec.Token(); err != nil {
+				return nil, jsonErr(err)
+			} else if t != json.Delim('[') {
+				return nil, &AlphabetError{Line: lineAt(dec.InputOffset()), Err: errors.New("characters must be a list")}
+			}
+			for dec.More() {
+				// The offset is after the previous token, so skip to the start of the entry
+				offset := dec.InputOffset()
+				for offset < int64(len(data)) && (data[offset] == ',' || unicode.IsSpace(rune(data[offset]))) {
+					offset++
+				}
+				entry := alphabetEntry{line: lineAt(offset)}
+				if err = dec.Decode(&entry); err != nil {
+					return nil, jsonErr(err)
+				}
+				f.Characters = append(f.Characters, entry)
+			}
+			if _, err = dec.Token(); err != nil {
+				return nil, jsonErr(err)
+			}
+			continue
+		default:
+			return nil, &AlphabetError{Line: lineAt(dec.InputOffset()), Err: fmt.Errorf("unknown field %q", key)}
+		}
+		if err = dec.Decode(value); err != nil {
+			return nil, jsonErr(err)
+		}
+	}
+	if _, err = dec.Token(); err != nil {
+		return nil, jsonErr(err)
+	}
+
+	return newAlphabet(f, lineAt(dec.InputOffset()))
+}
+
+func loadAlphabetCSV(r io.Reader) (*Alphabet, error) {
+	cr := csv.NewReader(r)
+	cr.Comment = '#'
+	cr.FieldsPerRecord = -1
+
+	f := alphabetFile{}
+	line := 1
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				return nil, &AlphabetError{Line: parseErr.Line, Err: parseErr.Err}
+			}
+			return nil, err
+		}
+		line, _ = cr.FieldPos(0)
+
+		if len(record) < 2 || len(record) > 3 {
+			return nil, &AlphabetError{Line: line, Err: fmt.Errorf("expected 2 or 3 fields, got %d", len(record))}
+		}
+
+		// Metadata
+		if strings.HasPrefix(record[0], "@") && utf8.RuneCountInString(record[0]) > 1 {
+			if len(record) != 2 {
+				return nil, &AlphabetError{Line: line, Err: fmt.Errorf("expected 2 fields for %s, got %d", record[0], len(record))}
+			}
+			switch record[0] {
+			case "@name":
+				f.Name = record[1]
+			case "@language":
+				f.Language = record[1]
+			case "@shiftIn":
+				f.ShiftIn = record[1]
+			case "@shiftOut":
+				f.ShiftOut = record[1]
+			default:
+				return nil, &AlphabetError{Line: line, Err: fmt.Errorf("unknown metadata %q", record[0])}
+			}
+			continue
+		}
+
+		entry := alphabetEntry{Text: record[0], Code: record[1], line: line}
+		if len(record) == 3 {
+			switch strings.TrimSpace(record[2]) {
+			case "encode-only":
+				entry.EncodeOnly = true
+			case "":
+			default:
+				return nil, &AlphabetError{Line: line, Err: fmt.Errorf("unknown flag %q", record[2])}
+			}
+		}
+		f.Characters = append(f.Characters, entry)
+	}
+
+	return newAlphabet(f, line)
+}
+
+// parseAlphabetCode validates and converts the code string of an alphabet definition
+func parseAlphabetCode(codeStr string) (Code, error) {
+	codeStr = strings.TrimSpace(codeStr)
+	if codeStr == "" {
+		return nil, errors.New("empty code")
+	}
+	for _, r := range codeStr {
+		switch r {
+		case '・', '.', '－', '-':
+		default:
+			return nil, fmt.Errorf("invalid rune %q in code %q", r, codeStr)
+		}
+	}
+	return FromCodeString(codeStr), nil
+}
+
+// newAlphabet validates the definition and creates the Alphabet.
+// end is the last line of the definition, for errors about the whole file
+func newAlphabet(f alphabetFile, end int) (*Alphabet, error) {
+	a := &Alphabet{Name: f.Name, Language: f.Language, Dictionary: NewDictionary()}
+
+	if (f.ShiftIn == "") != (f.ShiftOut == "") {
+		return nil, &AlphabetError{Line: end, Err: errors.New("shiftIn and shiftOut must both be given")}
+	}
+	if f.ShiftIn != "" {
+		var err error
+		if a.ShiftIn, err = parseAlphabetCode(f.ShiftIn); err != nil {
+			return nil, &AlphabetError{Line: end, Err: fmt.Errorf("shiftIn: %w", err)}
+		}
+		if a.ShiftOut, err = parseAlphabetCode(f.ShiftOut); err != nil {
+			return nil, &AlphabetError{Line: end, Err: fmt.Errorf("shiftOut: %w", err)}
+		}
+	}
+
+	if len(f.Characters) == 0 {
+		return nil, &AlphabetError{Line: end, Err: errors.New("no characters")}
+	}
+
+	// The line each text and (decodable) code was first defined on
+	texts := make(map[string]int, len(f.Characters))
+	codes := make(map[string]int, len(f.Characters))
+	for _, entry := range f.Characters {
+		if entry.Text == "" {
+			return nil, &AlphabetError{Line: entry.line, Err: errors.New("empty text")}
+		}
+		text := strings.ToLower(entry.Text)
+		if first, ok := texts[text]; ok {
+			return nil, &AlphabetError{Line: entry.line, Err: fmt.Errorf("%q is already defined on line %d", entry.Text, first)}
+		}
+		texts[text] = entry.line
+
+		c, err := parseAlphabetCode(entry.Code)
+		if err != nil {
+			return nil, &AlphabetError{Line: entry.line, Err: err}
+		}
+		if !entry.EncodeOnly {
+			if first, ok := codes[c.key()]; ok {
+				return nil, &AlphabetError{Line: entry.line, Err: fmt.Errorf(
+					"code %s is already used on line %d (mark one as encode-only)", c, first)}
+			}
+			codes[c.key()] = entry.line
+		}
+
+		a.Dictionary.addText(entry.Text, c, !entry.EncodeOnly)
+	}
+	return a, nil
+}
@@ -0,0 +1,96 @@
+package morse
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestLoadAlphabet_JSON(t *testing.T) {
+	a := assert.New(t)
+
+	alphabet, err := LoadAlphabet(strings.NewReader(`
+{
+  "name": "Toy",
+  "language": "xx",
+  "characters": [
+    {"text": "ä", "code": "・－・－", "encodeOnly": true},
+    {"text": "a", "code": "・－"},
+    {"text": "ch", "code": "－－－－"},
+    {"text": "æ", "code": ".-.-"}
+  ]
+}`))
+	if !a.NoError(err) {
+		return
+	}
+	a.Equal("Toy", alphabet.Name)
+	a.Equal("xx", alphabet.Language)
+	_, ok := alphabet.Shift()
+	a.False(ok)
+
+	c, err := FromTextWithOptions("ächa", EncoderOptions{Dictionary: alphabet.Dictionary})
+	a.NoError(err)
+	a.Equal("・－・－ －－－－ ・－", c.String())
+	a.Equal("ÆCHA", DecodeWithDictionary(c, alphabet.Dictionary))
+}
+
+func TestLoadAlphabet_CSV(t *testing.T) {
+	a := assert.New(t)
+
+	alphabet, err := LoadAlphabet(strings.NewReader(`# A subset of Wabun
+@name,Wabun
+@language,ja
+@shiftIn,－・・－－－
+@shiftOut,・・・－・
+イ,・－
+ロ,・－・－
+"、",・－・－・－
+ヰ,・－・・－,
+ヸ,・－・・－,encode-only
+`))
+	if !a.NoError(err) {
+		return
+	}
+	a.Equal("Wabun", alphabet.Name)
+	a.Equal("ja", alphabet.Language)
+	shift, ok := alphabet.Shift()
+	a.True(ok)
+	a.Equal(WabunIn, shift.In)
+	a.Equal(WabunOut, shift.Out)
+	a.Equal("イロ、ヰ", DecodeWithDictionary(FromCodeString("・－ ・－・－ ・－・－・－ ・－・・－"), alphabet.Dictionary))
+	a.Equal(alphabet.Dictionary.FromRune('ヰ'), alphabet.Dictionary.FromRune('ヸ'))
+}
+
+func TestLoadAlphabet_Errors(t *testing.T) {
+	a := assert.New(t)
+
+	for name, test := range map[string]struct {
+		def  string
+		line int
+		msg  string
+	}{
+		"empty":            {"  \n", 1, "empty definition"},
+		"json syntax":      {"{\n\"name\": \"x\",\n\"characters\": [\n{\"text\": \"a\" \"code\": \"・－\"}]}", 4, "invalid character"},
+		"json field":       {"{\n\"characters\": [\n{\"text\": \"a\", \"code\": \"・－\"},\n{\"txt\": \"b\"}]}", 4, "unknown field \"txt\""},
+		"json duplicate":   {"{\"characters\": [\n{\"text\": \"a\", \"code\": \"・－\"},\n\n  {\"text\": \"A\", \"code\": \"－・・・\"}]}", 4, "\"A\" is already defined on line 2"},
+		"json code":        {"{\"characters\": [\n{\"text\": \"a\", \"code\": \"・－\"},\n{\"text\": \"b\", \"code\": \"・x\"}]}", 3, "invalid rune 'x'"},
+		"json metadata":    {"{\n\"colour\": \"red\"}", 2, "unknown field \"colour\""},
+		"no characters":    {"{\"name\": \"x\",\n\"characters\": []\n}", 3, "no characters"},
+		"csv same code":    {"a,.-\nb,-...\n\nc,.-", 4, "code ・－ is already used on line 1"},
+		"csv fields":       {"# comment\na,.-\nb", 3, "expected 2 or 3 fields, got 1"},
+		"csv empty code":   {"a,.-\nb,  ", 2, "empty code"},
+		"csv flag":         {"a,.-\nb,-...,decode-only", 2, "unknown flag"},
+		"csv metadata":     {"@colour,red\na,.-", 1, "unknown metadata \"@colour\""},
+		"csv shift":        {"@shiftIn,-..---\na,.-", 2, "shiftIn and shiftOut must both be given"},
+		"csv quote":        {"a,.-\n\"b,-...", 2, "extraneous or missing \" in quoted-field"},
+		"csv blank lines":  {"\n\na,.-\nb", 4, "expected 2 or 3 fields, got 1"},
+		"json blank lines": {"\n \n{\n\"colour\": \"red\"}", 4, "unknown field \"colour\""},
+	} {
+		_, err := LoadAlphabet(strings.NewReader(test.def))
+		var alphabetErr *AlphabetError
+		if a.ErrorAs(err, &alphabetErr, name) {
+			a.Equal(test.line, alphabetErr.Line, name)
+			a.Contains(alphabetErr.Error(), test.msg, name)
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package morse
+
+var (
+	// Russian is the Dictionary for Russian (Cyrillic) Morse code
+	Russian = NewDictionary()
+
+	// Greek is the Dictionary for Greek Morse code
+	Greek = NewDictionary()
+
+	// Hebrew is the Dictionary for Hebrew Morse code
+	Hebrew = NewDictionary()
+
+	// Arabic is the Dictionary for Arabic Morse code
+	Arabic = NewDictionary()
+)
+
+// Alphabets contains the Dictionary of each national Morse alphabet,
+// keyed by ISO 639-1 language code. As most of the alphabets reuse the
+// same codes for different runes, the Dictionary to use has to be given
+// to a TextEncoder or Decoder explicitly, e.g.
+//
+//	FromTextWithDictionary("привет", Alphabets["ru"])
+var Alphabets = map[string]*Dictionary{
+	"en": DefaultDictionary,
+	"ja": Wabun,
+	"ru": Russian,
+	"el": Greek,
+	"he": Hebrew,
+	"ar": Arabic,
+	"ko": Korean,
+	"de": German,
+	"es": Spanish,
+	"fr": French,
+	"pl": Polish,
+	"eo": Esperanto,
+	"sv": Swedish,
+	"fi": Swedish,
+	"da": Danish,
+	"no": Danish,
+	"is": Icelandic,
+}
+
+// finalFormComposer is a Composer for alphabets where letters take a different form
+// at the end of a word, but are sent the same as the regular form
+type finalFormComposer map[rune]rune
+
+func (c finalFormComposer) Decompose(s string) string {
+	return s
+}
+
+func (c finalFormComposer) Compose(s string) string {
+	rs := []rune(s)
+	if len(rs) > 0 {
+		if final, ok := c[rs[len(rs)-1]]; ok {
+			rs[len(rs)-1] = final
+		}
+	}
+	return string(rs)
+}
+
+func init() {
+	Russian.AddCodeString('а', "・－")
+	Russian.AddCodeString('б', "－・・・")
+	Russian.AddCodeString('в', "・－－")
+	Russian.AddCodeString('г', "－－・")
+	Russian.AddCodeString('д', "－・・")
+	// Ё is sent the same as Е, so is added first to decode as Е
+	Russian.AddCodeString('ё', "・")
+	Russian.AddCodeString('е', "・")
+	Russian.AddCodeString('ж', "・・・－")
+	Russian.AddCodeString('з', "－－・・")
+	Russian.AddCodeString('и', "・・")
+	Russian.AddCodeString('й', "・－－－")
+	Russian.AddCodeString('к', "－・－")
+	Russian.AddCodeString('л', "・－・・")
+	Russian.AddCodeString('м', "－－")
+	Russian.AddCodeString('н', "－・")
+	Russian.AddCodeString('о', "－－－")
+	Russian.AddCodeString('п', "・－－・")
+	Russian.AddCodeString('р', "・－・")
+	Russian.AddCodeString('с', "・・・")
+	Russian.AddCodeString('т', "－")
+	Russian.AddCodeString('у', "・・－")
+	Russian.AddCodeString('ф', "・・－・")
+	Russian.AddCodeString('х', "・・・・")
+	Russian.AddCodeString('ц', "－・－・")
+	Russian.AddCodeString('ч', "－－－・")
+	Russian.AddCodeString('ш', "－－－－")
+	Russian.AddCodeString('щ', "－－・－")
+	Russian.AddCodeString('ъ', "－－・－－")
+	Russian.AddCodeString('ы', "－・－－")
+	Russian.AddCodeString('ь', "－・・－")
+	Russian.AddCodeString('э', "・・－・・")
+	Russian.AddCodeString('ю', "・・－－")
+	Russian.AddCodeString('я', "・－・－")
+	Russian.Add(' ', Space)
+	addDigits(Russian)
+	addPunctuation(Russian)
+
+	Greek.AddCodeString('α', "・－")
+	Greek.AddCodeString('β', "－・・・")
+	Greek.AddCodeString('γ', "－－・")
+	Greek.AddCodeString('δ', "－・・")
+	Greek.AddCodeString('ε', "・")
+	Greek.AddCodeString('ζ', "－－・・")
+	Greek.AddCodeString('η', "・・・・")
+	Greek.AddCodeString('θ', "－・－・")
+	Greek.AddCodeString('ι', "・・")
+	Greek.AddCodeString('κ', "－・－")
+	Greek.AddCodeString('λ', "・－・・")
+	Greek.AddCodeString('μ', "－－")
+	Greek.AddCodeString('ν', "－・")
+	Greek.AddCodeString('ξ', "－・・－")
+	Greek.AddCodeString('ο', "－－－")
+	Greek.AddCodeString('π', "・－－・")
+	Greek.AddCodeString('ρ', "・－・")
+	// The final sigma is sent the same as sigma, so is added first to decode as sigma
+	Greek.AddCodeString('ς', "・・・")
+	Greek.AddCodeString('σ', "・・・")
+	Greek.AddCodeString('τ', "－")
+	Greek.AddCodeString('υ', "－・－－")
+	Greek.AddCodeString('φ', "・・－・")
+	Greek.AddCodeString('χ', "－－－－")
+	Greek.AddCodeString('ψ', "－－・－")
+	Greek.AddCodeString('ω', "・－－")
+	Greek.Add(' ', Space)
+	addDigits(Greek)
+	addPunctuation(Greek)
+
+	// Final forms are sent the same as the regular letter, so are added
+	// first to decode as the latter (then the composer converts back)
+	Hebrew.AddCodeString('ך', "－・－")
+	Hebrew.AddCodeString('ם', "－－")
+	Hebrew.AddCodeString('ן', "－・")
+	Hebrew.AddCodeString('ף', "・－－・")
+	Hebrew.AddCodeString('ץ', "・－－")
+	Hebrew.AddCodeString('א', "・－")
+	Hebrew.AddCodeString('ב', "－・・・")
+	Hebrew.AddCodeString('ג', "－－・")
+	Hebrew.AddCodeString('ד', "－・・")
+	Hebrew.AddCodeString('ה', "－－－")
+	Hebrew.AddCodeString('ו', "・")
+	Hebrew.AddCodeString('ז', "－－・・")
+	Hebrew.AddCodeString('ח', "・・・・")
+	Hebrew.AddCodeString('ט', "・・－")
+	Hebrew.AddCodeString('י', "・・")
+	Hebrew.AddCodeString('כ', "－・－")
+	Hebrew.AddCodeString('ל', "・－・・")
+	Hebrew.AddCodeString('מ', "－－")
+	Hebrew.AddCodeString('נ', "－・")
+	Hebrew.AddCodeString('ס', "－・－・")
+	Hebrew.AddCodeString('ע', "・－－－")
+	Hebrew.AddCodeString('פ', "・－－・")
+	Hebrew.AddCodeString('צ', "・－－")
+	Hebrew.AddCodeString('ק', "－－・－")
+	Hebrew.AddCodeString('ר', "・－・")
+	Hebrew.AddCodeString('ש', "・・・")
+	Hebrew.AddCodeString('ת', "－")
+	Hebrew.Add(' ', Space)
+	addDigits(Hebrew)
+	addPunctuation(Hebrew)
+	Hebrew.SetComposer(finalFormComposer{'כ': 'ך', 'מ': 'ם', 'נ': 'ן', 'פ': 'ף', 'צ': 'ץ'})
+
+	Arabic.AddCodeString('ا', "・－")
+	Arabic.AddCodeString('ب', "－・・・")
+	Arabic.AddCodeString('ت', "－")
+	Arabic.AddCodeString('ث', "－・－・")
+	Arabic.AddCodeString('ج', "・－－－")
+	Arabic.AddCodeString('ح', "・・・・")
+	Arabic.AddCodeString('خ', "－－－")
+	Arabic.AddCodeString('د', "－・・")
+	Arabic.AddCodeString('ذ', "－－・・")
+	Arabic.AddCodeString('ر', "・－・")
+	Arabic.AddCodeString('ز', "－－－・")
+	Arabic.AddCodeString('س', "・・・")
+	Arabic.AddCodeString('ش', "－－－－")
+	Arabic.AddCodeString('ص', "－・・－")
+	Arabic.AddCodeString('ض', "・・・－")
+	Arabic.AddCodeString('ط', "・・－")
+	Arabic.AddCodeString('ظ', "－・－－")
+	Arabic.AddCodeString('ع', "・－・－")
+	Arabic.AddCodeString('غ', "－－・")
+	Arabic.AddCodeString('ف', "・・－・")
+	Arabic.AddCodeString('ق', "－－・－")
+	Arabic.AddCodeString('ك', "－・－")
+	Arabic.AddCodeString('ل', "・－・・")
+	Arabic.AddCodeString('م', "－－")
+	Arabic.AddCodeString('ن', "－・")
+	Arabic.AddCodeString('ه', "・・－・・")
+	Arabic.AddCodeString('و', "・－－")
+	Arabic.AddCodeString('ي', "・・")
+	Arabic.AddCodeString('ء', "・")
+	Arabic.Add(' ', Space)
+	// Arabic-Indic digits are sent the same as the Latin
+	// digits, so are added first to decode as the latter
+	for i, c := range []Code{Zero, One, Two, Three, Four, Five, Six, Seven, Eight, Nine} {
+		Arabic.Add('٠'+rune(i), c)
+	}
+	addDigits(Arabic)
+	addPunctuation(Arabic)
+}
@@ -0,0 +1,37 @@
+package morse
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestAlphabets(t *testing.T) {
+	a := assert.New(t)
+
+	a.Equal("ПРИВЕТ 123", DecodeWithDictionary(FromTextWithDictionary("привет 123", Alphabets["ru"]), Alphabets["ru"]))
+	a.Equal("ΚΑΛΗΜΕΡΑ", DecodeWithDictionary(FromTextWithDictionary("Καλημερα", Alphabets["el"]), Alphabets["el"]))
+	a.Equal("שלום", DecodeWithDictionary(FromTextWithDictionary("שלום", Alphabets["he"]), Alphabets["he"]))
+	a.Equal("مرحبا", DecodeWithDictionary(FromTextWithDictionary("مرحبا", Alphabets["ar"]), Alphabets["ar"]))
+
+	// The same code should decode to a different script depending on the alphabet
+	code := FromText("sos")
+	a.Equal("SOS", DecodeWithDictionary(code, Alphabets["en"]))
+	a.Equal("СОС", DecodeWithDictionary(code, Alphabets["ru"]))
+	a.Equal("ΣΟΣ", DecodeWithDictionary(code, Alphabets["el"]))
+	a.Equal("שהש", DecodeWithDictionary(code, Alphabets["he"]))
+	a.Equal("سخس", DecodeWithDictionary(code, Alphabets["ar"]))
+	a.Equal("ラレラ", DecodeWithDictionary(code, Alphabets["ja"]))
+
+	// Digits and punctuation are shared
+	a.Equal(FromText("1.").String(), FromTextWithDictionary("1.", Russian).String())
+	a.Equal(FromText("1").String(), FromTextWithDictionary("١", Arabic).String())
+
+	// Like the Latin alphabets, the space is an entry of each alphabet
+	for _, d := range []*Dictionary{Russian, Greek, Hebrew, Arabic} {
+		a.Equal(Space, d.FromRune(' '))
+	}
+
+	// Final forms are sent as the regular form
+	a.Equal(FromTextWithDictionary("σοσ", Greek).String(), FromTextWithDictionary("σος", Greek).String())
+	a.Equal("מים", DecodeWithDictionary(FromTextWithDictionary("מימ", Hebrew), Hebrew))
+}
@@ -0,0 +1,77 @@
+package morse
+
+// Signals only used by American (railroad) Morse
+const (
+	// LongDah is the long dash of the American Morse L, which is
+	// twice as long as an American dah (see AmericanTiming). Equivalent to
+	//  NewSignal(true, 4)
+	LongDah = Signal(0b00000111)
+
+	// ExtraLongDah is the extra-long dash of the American Morse zero,
+	// which is longer than a LongDah (see AmericanTiming). Equivalent to
+	//  NewSignal(true, 5)
+	ExtraLongDah = Signal(0b00001001)
+
+	// InternalSpace is the space within some American Morse characters
+	// (e.g. O is ・ ・), which is twice as long as a SignalSpace, but
+	// shorter than a RuneSpace. Its string representation is a thin
+	// space (U+2009). Equivalent to
+	//  NewSignal(false, 2)
+	InternalSpace = Signal(0b00000010)
+)
+
+// American is the Dictionary for American (railroad) Morse code, as used on
+// landline telegraphs. As well as dits and dahs, it uses the LongDah, ExtraLongDah
+// and InternalSpace signals. American Morse is timed differently to International
+// Morse (see AmericanTiming), which Code.Duration only uses if the code has one of
+// those signals, so use Code.DurationWithTiming with AmericanTiming to drive a sounder
+var American = NewDictionary()
+
+// americanSignal returns whether the signal is only used by American Morse
+func americanSignal(s Signal) bool {
+	return s == LongDah || s == ExtraLongDah || s == InternalSpace
+}
+
+// americanCode creates American Morse Code from a string, where '.' is a Dit,
+// '-' is a Dah, 'L' is a LongDah, '0' is an ExtraLongDah and ' ' is an
+// InternalSpace. A SignalSpace is added between adjacent audible signals
+func americanCode(codeStr string) Code {
+	c := make(Code, 0, len(codeStr)*2)
+	for _, r := range codeStr {
+		var s Signal
+		switch r {
+		case '.':
+			s = Dit
+		case '-':
+			s = Dah
+		case 'L':
+			s = LongDah
+		case '0':
+			s = ExtraLongDah
+		case ' ':
+			c = append(c, InternalSpace)
+			continue
+		}
+		if len(c) > 0 && c[len(c)-1].Audible() {
+			c = append(c, SignalSpace)
+		}
+		c = append(c, s)
+	}
+	return c
+}
+
+func init() {
+	for r, codeStr := range map[rune]string{
+		'a': ".-", 'b': "-...", 'c': ".. .", 'd': "-..", 'e': ".", 'f': ".-.",
+		'g': "--.", 'h': "....", 'i': "..", 'j': "-.-.", 'k': "-.-", 'l': "L",
+		'm': "--", 'n': "-.", 'o': ". .", 'p': ".....", 'q': "..-.", 'r': ". ..",
+		's': "...", 't': "-", 'u': "..-", 'v': "...-", 'w': ".--", 'x': ".-..",
+		'y': ".. ..", 'z': "... .", '&': ". ...",
+		'1': ".--.", '2': "..-..", '3': "...-.", '4': "....-", '5': "---",
+		'6': "......", '7': "--..", '8': "-....", '9': "-..-", '0': "0",
+		'.': "..--..", ',': ".-.-", '?': "-..-.", '!': "---.",
+	} {
+		American.Add(r, americanCode(codeStr))
+	}
+	American.Add(' ', Space)
+}
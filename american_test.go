@@ -0,0 +1,71 @@
+package morse
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestAmerican(t *testing.T) {
+	a := assert.New(t)
+
+	c, err := FromTextWithOptions("Cool 10", EncoderOptions{Dictionary: American})
+	a.NoError(err)
+	a.Equal(JoinWords(
+		JoinLetters(
+			Code{Dit, SignalSpace, Dit, InternalSpace, Dit},
+			Code{Dit, InternalSpace, Dit},
+			Code{Dit, InternalSpace, Dit},
+			Code{LongDah}),
+		JoinLetters(
+			JoinSignals(Code{Dit}, Code{Dah}, Code{Dah}, Code{Dit}),
+			Code{ExtraLongDah})), c)
+	a.Equal("・・ ・ ・ ・ ・ ・ ⸺  ・－－・ ⸻", c.String())
+	a.Equal("COOL 10", DecodeWithDictionary(c, American))
+
+	// The internal spaces distinguish the letters from their International equivalents
+	a.Equal("?", Decode(americanCode(".. .")))
+	a.Equal("R", DecodeWithDictionary(americanCode(". .."), American))
+	a.Equal("S", DecodeWithDictionary(americanCode("..."), American))
+
+	// Code with American signals uses American timing, where L is twice
+	// as long as T, and 0 is two and a half times as long
+	dah := Dah.DurationWithTiming(20, 0, AmericanTiming)
+	a.Equal(2*dah, Code{LongDah}.Duration(20, 0))
+	a.Equal(5*dah/2, Code{ExtraLongDah}.Duration(20, 0))
+	a.Equal(2*dah, americanCode(". .").Duration(20, 0))
+	hello := FromTextWithDictionary("hello", American)
+	a.Equal(hello.DurationWithTiming(20, 0, AmericanTiming), hello.Duration(20, 0))
+	a.Equal(hello.DitDurationWithTiming(AmericanTiming), hello.DitDuration())
+	a.Equal(uint(4), LongDah.DitDuration())
+	a.Equal(uint(5), ExtraLongDah.DitDuration())
+}
+
+func TestAmericanTiming(t *testing.T) {
+	a := assert.New(t)
+
+	a.Equal(uint(1), AmericanTiming.DitDuration(Dit))
+	a.Equal(uint(2), AmericanTiming.DitDuration(Dah))
+	a.Equal(uint(4), AmericanTiming.DitDuration(LongDah))
+	a.Equal(uint(5), AmericanTiming.DitDuration(ExtraLongDah))
+	a.Equal(uint(2), AmericanTiming.DitDuration(InternalSpace))
+	a.Equal(uint(6), AmericanTiming.DitDuration(WordSpace))
+	// Other signals last their own duration
+	a.Equal(uint(10), AmericanTiming.DitDuration(NewSignal(false, 10)))
+
+	// O is a dit, an internal space and a dit, and L is a long dash
+	a.Equal(uint(4), americanCode(". .").DitDurationWithTiming(AmericanTiming))
+	a.Equal(Code{Dit, Dit, Dit, Dit}.DurationWithTiming(20, 0, AmericanTiming),
+		Code{LongDah}.DurationWithTiming(20, 0, AmericanTiming))
+
+	// The WPM is based on PARIS sent in American Morse
+	paris := append(FromTextWithDictionary("paris", American), WordSpace)
+	a.Equal(paris, AmericanTiming.StandardWord)
+	a.Equal(time.Minute, (paris.DurationWithTiming(20, 0, AmericanTiming) * 20).Round(time.Second))
+	a.Equal(time.Minute, (paris.DurationWithTiming(20, 15, AmericanTiming) * 15).Round(time.Second))
+	a.Greater(paris.DurationWithTiming(20, 15, AmericanTiming), paris.DurationWithTiming(20, 0, AmericanTiming))
+
+	// The zero value is International Morse timing
+	a.Equal(JoinWords(S, O, S).Duration(20, 15), JoinWords(S, O, S).DurationWithTiming(20, 15, Timing{}))
+	a.Equal(Dah.Duration(20, 0), Dah.DurationWithTiming(20, 0, Timing{}))
+}
@@ -49,108 +49,114 @@ var (
 	At           = FromCodeString("・－－・－・")
 	LeftBracket  = FromCodeString("－・－－・")
 	RightBracket = FromCodeString("－・－－・－")
+
+	Apostrophe    = FromCodeString("・－－－－・")
+	QuotationMark = FromCodeString("・－・・－・")
+	Colon         = FromCodeString("－－－・・・")
+	Equals        = FromCodeString("－・・・－")
+	Plus          = FromCodeString("・－・－・")
+
+	// The following aren't in the ITU standard, but are commonly used
+
+	ExclamationMark = FromCodeString("－・－・－－")
+	Ampersand       = FromCodeString("・－・・・")
+	Semicolon       = FromCodeString("－・－・－・")
+	Dollar          = FromCodeString("・・・－・・－")
+	Underscore      = FromCodeString("・・－－・－")
 )
 
+// ITUDictionary contains only the characters in the ITU standard for
+// International Morse Code (ITU-R M.1677-1). Use it (with EncoderOptions.Strict)
+// to refuse characters that the standard doesn't allow, whereas
+// DefaultDictionary also contains common non-standard characters
+var ITUDictionary = NewDictionary()
+
 func init() {
-	Dictionary.Add('a', A)
-	Dictionary.Add('b', B)
-	Dictionary.Add('c', C)
-	Dictionary.Add('d', D)
-	Dictionary.Add('e', E)
-	Dictionary.Add('f', F)
-	Dictionary.Add('g', G)
-	Dictionary.Add('h', H)
-	Dictionary.Add('i', I)
-	Dictionary.Add('j', J)
-	Dictionary.Add('k', K)
-	Dictionary.Add('l', L)
-	Dictionary.Add('m', M)
-	Dictionary.Add('n', N)
-	Dictionary.Add('o', O)
-	Dictionary.Add('p', P)
-	Dictionary.Add('q', Q)
-	Dictionary.Add('r', R)
-	Dictionary.Add('s', S)
-	Dictionary.Add('t', T)
-	Dictionary.Add('u', U)
-	Dictionary.Add('v', V)
-	Dictionary.Add('w', W)
-	Dictionary.Add('x', X)
-	Dictionary.Add('y', Y)
-	Dictionary.Add('z', Z)
+	addLetters(DefaultDictionary)
+	DefaultDictionary.Add(' ', Space)
+	addDigits(DefaultDictionary)
+	addPunctuation(DefaultDictionary)
+	addExtendedPunctuation(DefaultDictionary)
+	addExtendedLetters(DefaultDictionary)
 
-	Dictionary.Add('1', One)
-	Dictionary.Add('2', Two)
-	Dictionary.Add('3', Three)
-	Dictionary.Add('4', Four)
-	Dictionary.Add('5', Five)
-	Dictionary.Add('6', Six)
-	Dictionary.Add('7', Seven)
-	Dictionary.Add('8', Eight)
-	Dictionary.Add('9', Nine)
-	Dictionary.Add('0', Zero)
+	addLetters(ITUDictionary)
+	ITUDictionary.Add(' ', Space)
+	addDigits(ITUDictionary)
+	addPunctuation(ITUDictionary)
+}
 
-	Dictionary.Add(' ', Space)
+// addLetters adds the English letters (and É, which is in the ITU
+// standard), which are shared by all the Latin alphabets, to the dictionary
+func addLetters(d *Dictionary) {
+	d.Add('a', A)
+	d.Add('b', B)
+	d.Add('c', C)
+	d.Add('d', D)
+	d.Add('e', E)
+	d.Add('f', F)
+	d.Add('g', G)
+	d.Add('h', H)
+	d.Add('i', I)
+	d.Add('j', J)
+	d.Add('k', K)
+	d.Add('l', L)
+	d.Add('m', M)
+	d.Add('n', N)
+	d.Add('o', O)
+	d.Add('p', P)
+	d.Add('q', Q)
+	d.Add('r', R)
+	d.Add('s', S)
+	d.Add('t', T)
+	d.Add('u', U)
+	d.Add('v', V)
+	d.Add('w', W)
+	d.Add('x', X)
+	d.Add('y', Y)
+	d.Add('z', Z)
+	d.AddCodeString('é', "・・－・・")
+}
+
+// addDigits adds the digits, which are shared by most alphabets, to the dictionary
+func addDigits(d *Dictionary) {
+	d.Add('1', One)
+	d.Add('2', Two)
+	d.Add('3', Three)
+	d.Add('4', Four)
+	d.Add('5', Five)
+	d.Add('6', Six)
+	d.Add('7', Seven)
+	d.Add('8', Eight)
+	d.Add('9', Nine)
+	d.Add('0', Zero)
+}
 
-	Dictionary.Add('.', Period)
-	Dictionary.Add(',', Comma)
-	Dictionary.Add('?', QuestionMark)
-	Dictionary.Add('-', Dash)
-	Dictionary.Add('/', ForwardSlash)
-	Dictionary.Add('@', At)
-	Dictionary.Add('(', LeftBracket)
-	Dictionary.Add(')', RightBracket)
+// addPunctuation adds the ITU standard punctuation, which is
+// shared by most non-Japanese alphabets, to the dictionary
+func addPunctuation(d *Dictionary) {
+	d.Add('.', Period)
+	d.Add(',', Comma)
+	d.Add('?', QuestionMark)
+	d.Add('-', Dash)
+	d.Add('/', ForwardSlash)
+	d.Add('@', At)
+	d.Add('(', LeftBracket)
+	d.Add(')', RightBracket)
+	d.Add('\'', Apostrophe)
+	d.Add('"', QuotationMark)
+	d.Add(':', Colon)
+	d.Add('=', Equals)
+	d.Add('+', Plus)
+	// The multiplication sign is sent the same as X
+	d.addText("×", X, false)
+}
 
-	/* todo uncomment once jp is handled properly
-	Dictionary.AddCodeString('イ',"・－")
-	Dictionary.AddCodeString('ロ',"・－・－")
-	Dictionary.AddCodeString('ハ',"－・・・")
-	Dictionary.AddCodeString('ニ',"－・－・")
-	Dictionary.AddCodeString('ホ',"－・・")
-	Dictionary.AddCodeString('ヘ',"・")
-	Dictionary.AddCodeString('ト',"・・－・・")
-	Dictionary.AddCodeString('チ',"・・－・")
-	Dictionary.AddCodeString('リ',"－－・")
-	Dictionary.AddCodeString('ヌ',"・・・・")
-	Dictionary.AddCodeString('ル',"－・－－・")
-	Dictionary.AddCodeString('ヲ',"・－－－")
-	Dictionary.AddCodeString('ワ',"－・－")
-	Dictionary.AddCodeString('カ',"・－・・")
-	Dictionary.AddCodeString('ヨ',"－－")
-	Dictionary.AddCodeString('タ',"－・")
-	Dictionary.AddCodeString('レ',"－－－")
-	Dictionary.AddCodeString('ソ',"－－－・")
-	Dictionary.AddCodeString('ツ',"・－－・")
-	Dictionary.AddCodeString('ネ',"－－・－")
-	Dictionary.AddCodeString('ナ',"・－・")
-	Dictionary.AddCodeString('ラ',"・・・")
-	Dictionary.AddCodeString('ム',"－")
-	Dictionary.AddCodeString('ウ',"・・－")
-	Dictionary.AddCodeString('ヰ',"・－・・－")
-	Dictionary.AddCodeString('ノ',"・・－－")
-	Dictionary.AddCodeString('オ',"・－・・・")
-	Dictionary.AddCodeString('ク',"・・・－")
-	Dictionary.AddCodeString('ヤ',"・－－")
-	Dictionary.AddCodeString('マ',"－・・－")
-	Dictionary.AddCodeString('ケ',"－・－－")
-	Dictionary.AddCodeString('フ',"－－・・")
-	Dictionary.AddCodeString('コ',"－－－－")
-	Dictionary.AddCodeString('エ',"－・－－－")
-	Dictionary.AddCodeString('テ',"・－・－－")
-	Dictionary.AddCodeString('ア',"－－・－－")
-	Dictionary.AddCodeString('サ',"－・－・－")
-	Dictionary.AddCodeString('キ',"－・－・・")
-	Dictionary.AddCodeString('ユ',"－・・－－")
-	Dictionary.AddCodeString('メ',"－・・・－")
-	Dictionary.AddCodeString('ミ',"・・－・－")
-	Dictionary.AddCodeString('シ',"－－・－・")
-	Dictionary.AddCodeString('ヱ',"・－－・・")
-	Dictionary.AddCodeString('ヒ',"－－・・－")
-	Dictionary.AddCodeString('モ',"－・・－・")
-	Dictionary.AddCodeString('セ',"・－－－・")
-	Dictionary.AddCodeString('ス',"－－－・－")
-	Dictionary.AddCodeString('ン',"・－・－・")
-	Dictionary.AddCodeString('゛',"・・")
-	Dictionary.AddCodeString('゜',"・・－－・")
-	*/
+// addExtendedPunctuation adds the common punctuation
+// that isn't in the ITU standard to the dictionary
+func addExtendedPunctuation(d *Dictionary) {
+	d.Add('!', ExclamationMark)
+	d.Add('&', Ampersand)
+	d.Add(';', Semicolon)
+	d.Add('$', Dollar)
+	d.Add('_', Underscore)
 }
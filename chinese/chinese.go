@@ -0,0 +1,267 @@
+// Package chinese converts between Hanzi and Chinese (Standard) Telegraph Code, where
+// each character is sent as a group of four digits (e.g. 中 is sent as 0022).
+// Like real telegraph traffic, every four digit group is converted by default.
+// To send Hanzi mixed with numbers, Options.Marked puts each run of groups
+// between Markers, so the numbers aren't mistaken for groups.
+//
+// The embedded codebook.txt contains one code per line, followed by the characters
+// with that code separated by spaces. The first character is the one that is
+// decoded, the rest are variants (e.g. simplified forms) that are only encoded.
+// Lines starting with # are comments. The codebook is generated from the
+// kTaiwanTelegraph and kMainlandTelegraph fields of the Unicode Han Database
+// (Unihan) by gen.go, more codes can be added with Load
+package chinese
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"github.com/bhollier/morse"
+	"io"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+//go:generate go run gen.go -o codebook.txt
+
+// Unknown is the rune that FromGroups gives for a four digit group that isn't in the codebook
+const Unknown = '〓'
+
+// UnknownGroup is the group that ToGroups gives for Hanzi that aren't in the
+// codebook. It isn't the code of any character, so FromGroups gives Unknown
+const UnknownGroup = "0000"
+
+// Marker is put before and after each run of groups (unless the run ends the
+// text) when Options.Marked is set. It is made of characters that can be sent
+// in International Morse, e.g. "中文 2024" is sent as "/ZH 0022 2429 /ZH 2024"
+const Marker = "/ZH"
+
+// Options configures the conversion between Hanzi and groups.
+// The zero value is the default configuration
+type Options struct {
+	// Marked makes ToGroups put each run of groups between Markers, and
+	// FromGroups only convert the groups between Markers, so that four digit
+	// numbers in the rest of the text are left as-is. By default, every four
+	// digit group is converted, as in real telegraph traffic
+	Marked bool
+}
+
+//go:embed codebook.txt
+var codebookFile []byte
+
+var (
+	mu          sync.RWMutex
+	hanziToCode = make(map[rune]string)
+	codeToHanzi = make(map[string]rune)
+	// The characters of each code, including the variants
+	codeToAll = make(map[string][]rune)
+)
+
+func init() {
+	err := Load(strings.NewReader(string(codebookFile)))
+	if err != nil {
+		panic(err)
+	}
+}
+
+// Load adds the codes from the given reader to the codebook, in the same format as
+// the embedded codebook.txt. Codes that are already in the codebook are replaced,
+// so the characters they had are no longer encoded as them
+func Load(r io.Reader) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		code := fields[0]
+		if !isCode(code) || code == UnknownGroup {
+			return fmt.Errorf("line %d: invalid code %q", line, code)
+		}
+		if len(fields) == 1 {
+			return fmt.Errorf("line %d: code %s has no characters", line, code)
+		}
+		hanzi := make([]rune, 0, len(fields)-1)
+		for _, field := range fields[1:] {
+			rs := []rune(field)
+			if len(rs) != 1 {
+				return fmt.Errorf("line %d: expected a single character, got %q", line, field)
+			}
+			hanzi = append(hanzi, rs[0])
+		}
+
+		// Remove the previous characters of the code
+		for _, h := range codeToAll[code] {
+			if hanziToCode[h] == code {
+				delete(hanziToCode, h)
+			}
+		}
+		for _, h := range hanzi {
+			// The character may have moved from another code
+			if prev, ok := hanziToCode[h]; ok && prev != code {
+				removeHanzi(prev, h)
+			}
+			hanziToCode[h] = code
+		}
+		codeToHanzi[code] = hanzi[0]
+		codeToAll[code] = hanzi
+	}
+	return scanner.Err()
+}
+
+// removeHanzi removes the character from the characters of the code,
+// removing the code if it has no characters left
+func removeHanzi(code string, h rune) {
+	all := codeToAll[code]
+	for i, other := range all {
+		if other == h {
+			all = append(all[:i:i], all[i+1:]...)
+			break
+		}
+	}
+	if len(all) == 0 {
+		delete(codeToAll, code)
+		delete(codeToHanzi, code)
+		return
+	}
+	codeToAll[code] = all
+	codeToHanzi[code] = all[0]
+}
+
+// isCode returns whether s is a four digit code
+func isCode(s string) bool {
+	if len(s) != 4 {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// ToGroups converts the Hanzi in text into four digit groups, which are separated
+// from each other and the rest of the text by spaces. Hanzi that aren't in the
+// codebook are replaced with UnknownGroup, and other text is left as-is
+func ToGroups(text string) string {
+	return ToGroupsWithOptions(text, Options{})
+}
+
+// ToGroupsWithOptions converts the Hanzi in text into four digit groups, configured by opts.
+// See ToGroups
+func ToGroupsWithOptions(text string, opts Options) string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	sb := strings.Builder{}
+	// Whether the last rune written was a group
+	lastWasGroup := false
+	for _, r := range text {
+		if !unicode.Is(unicode.Han, r) {
+			if lastWasGroup {
+				if opts.Marked {
+					sb.WriteRune(' ')
+					sb.WriteString(Marker)
+				}
+				if !unicode.IsSpace(r) {
+					sb.WriteRune(' ')
+				}
+			}
+			sb.WriteRune(r)
+			lastWasGroup = false
+			continue
+		}
+
+		if sb.Len() > 0 && !strings.HasSuffix(sb.String(), " ") {
+			sb.WriteRune(' ')
+		}
+		if opts.Marked && !lastWasGroup {
+			sb.WriteString(Marker)
+			sb.WriteRune(' ')
+		}
+		if code, ok := hanziToCode[r]; ok {
+			sb.WriteString(code)
+		} else {
+			sb.WriteString(UnknownGroup)
+		}
+		lastWasGroup = true
+	}
+	return sb.String()
+}
+
+// FromGroups converts every four digit group in text back into Hanzi. Groups
+// that aren't in the codebook are replaced with Unknown, and other text is left as-is
+func FromGroups(text string) string {
+	return FromGroupsWithOptions(text, Options{})
+}
+
+// FromGroupsWithOptions converts the four digit groups in text back into Hanzi,
+// configured by opts. With Options.Marked, only the groups between Markers are
+// converted (a Marker without another after it lasts until the end of the text),
+// and the Markers are removed. See FromGroups
+func FromGroupsWithOptions(text string, opts Options) string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	sb := strings.Builder{}
+	// Whether the fields are groups (i.e. between Markers),
+	// and whether the last field written was a group
+	inGroups, lastWasGroup := !opts.Marked, false
+	for _, field := range strings.Fields(text) {
+		if opts.Marked && field == Marker {
+			inGroups = !inGroups
+			continue
+		}
+		if !inGroups || !isCode(field) {
+			if sb.Len() > 0 {
+				sb.WriteRune(' ')
+			}
+			sb.WriteString(field)
+			lastWasGroup = false
+			continue
+		}
+
+		// Groups aren't separated by spaces from each other, like written Chinese
+		if sb.Len() > 0 && !lastWasGroup {
+			sb.WriteRune(' ')
+		}
+		if hanzi, ok := codeToHanzi[field]; ok {
+			sb.WriteRune(hanzi)
+		} else {
+			sb.WriteRune(Unknown)
+		}
+		lastWasGroup = true
+	}
+	return sb.String()
+}
+
+// FromText returns the Morse code of the given text, with the Hanzi converted
+// into four digit groups. See ToGroups
+func FromText(text string) morse.Code {
+	return FromTextWithOptions(text, Options{})
+}
+
+// FromTextWithOptions returns the Morse code of the given text, with the Hanzi
+// converted into four digit groups configured by opts. See ToGroupsWithOptions
+func FromTextWithOptions(text string, opts Options) morse.Code {
+	return morse.FromText(ToGroupsWithOptions(text, opts))
+}
+
+// Decode returns the text of the given Morse code, with the four digit
+// groups converted back into Hanzi. See FromGroups
+func Decode(code morse.Code) string {
+	return DecodeWithOptions(code, Options{})
+}
+
+// DecodeWithOptions returns the text of the given Morse code, with the four digit
+// groups converted back into Hanzi configured by opts. See FromGroupsWithOptions
+func DecodeWithOptions(code morse.Code, opts Options) string {
+	return FromGroupsWithOptions(morse.Decode(code), opts)
+}
@@ -0,0 +1,102 @@
+package chinese
+
+import (
+	"github.com/bhollier/morse"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+var marked = Options{Marked: true}
+
+func TestToGroups(t *testing.T) {
+	a := assert.New(t)
+
+	a.Equal("0022 2429", ToGroups("中文"))
+	a.Equal("HI 0022 0948 , 2024", ToGroups("HI 中国, 2024"))
+	a.Equal("0554 0079 OK 0086", ToGroups("北京OK人"))
+	// Traditional and simplified characters have the same code
+	a.Equal(ToGroups("國"), ToGroups("国"))
+
+	a.Equal("/ZH 0022 2429", ToGroupsWithOptions("中文", marked))
+	a.Equal("HI /ZH 0022 0948 /ZH , 2024", ToGroupsWithOptions("HI 中国, 2024", marked))
+	a.Equal("/ZH 0554 0079 /ZH OK /ZH 0086", ToGroupsWithOptions("北京OK人", marked))
+	a.Equal("/ZH 0022 2429 /ZH 2024", ToGroupsWithOptions("中文 2024", marked))
+}
+
+func TestFromGroups(t *testing.T) {
+	a := assert.New(t)
+
+	// Every four digit group is converted, as in real telegraph traffic
+	a.Equal("中文", FromGroups("0022 2429"))
+	a.Equal("中文", Decode(morse.FromText("0022 2429")))
+	a.Equal("HI 中國 , 22", FromGroups("HI 0022 0948 , 22"))
+
+	a.Equal("中文", FromGroupsWithOptions("/ZH 0022 2429", marked))
+	a.Equal("HI 中國 , 2024", FromGroupsWithOptions("HI /ZH 0022 0948 /ZH , 2024", marked))
+	// Numbers that aren't between markers aren't groups
+	a.Equal("2024 0022", FromGroupsWithOptions("2024 0022", marked))
+	a.Equal("北京 OK 0086", FromGroupsWithOptions("/ZH 0554 0079 /ZH OK 0086", marked))
+	a.Equal("中文 2024", FromGroupsWithOptions("/ZH 0022 2429 /ZH 2024", marked))
+	a.Equal("", FromGroupsWithOptions("/ZH", marked))
+}
+
+func TestRoundTrip(t *testing.T) {
+	a := assert.New(t)
+
+	// Every code in the codebook decodes to a character that encodes to it
+	for code, hanzi := range codeToHanzi {
+		a.Equal(code, ToGroups(string(hanzi)), code)
+		a.Equal(string(hanzi), FromGroups(ToGroups(string(hanzi))), code)
+	}
+	for hanzi, code := range hanziToCode {
+		a.Equal(code, ToGroups(FromGroups(code)), string(hanzi))
+	}
+
+	for _, text := range []string{"中華人民共和國", "毛澤東"} {
+		a.Equal(text, FromGroups(ToGroups(text)), text)
+		a.Equal(text, Decode(FromText(text)), text)
+	}
+	for _, text := range []string{"中華人民共和國", "毛澤東 1949", "中文 OK"} {
+		a.Equal(text, FromGroupsWithOptions(ToGroupsWithOptions(text, marked), marked), text)
+		a.Equal(strings.ToUpper(text), DecodeWithOptions(FromTextWithOptions(text, marked), marked), text)
+	}
+}
+
+func TestUnknown(t *testing.T) {
+	a := assert.New(t)
+
+	// Hanzi that aren't in the codebook
+	a.Equal("0022 "+UnknownGroup, ToGroups("中龘"))
+	a.Equal("中"+string(Unknown), FromGroups(ToGroups("中龘")))
+	a.Equal("中"+string(Unknown), Decode(FromText("中龘")))
+
+	// Codes that aren't in the codebook
+	a.Equal(string(Unknown)+"中", FromGroups("9999 0022"))
+	a.Equal(string(Unknown), DecodeWithOptions(morse.FromText("/ZH 9999"), marked))
+}
+
+func TestLoad(t *testing.T) {
+	a := assert.New(t)
+
+	a.NoError(Load(strings.NewReader("# Test codes\n9998 龘\n")))
+	a.Equal("9998", ToGroups("龘"))
+	a.Equal("龘", FromGroups("9998"))
+
+	// Replacing a code removes its previous characters
+	a.NoError(Load(strings.NewReader("9998 靐\n")))
+	a.Equal("靐", FromGroups("9998"))
+	a.Equal("9998", ToGroups("靐"))
+	a.Equal(UnknownGroup, ToGroups("龘"))
+
+	// A character that moves to another code is no longer decoded from the old one
+	a.NoError(Load(strings.NewReader("9997 靐\n")))
+	a.Equal("9997", ToGroups("靐"))
+	a.Equal(string(Unknown), FromGroups("9998"))
+
+	a.EqualError(Load(strings.NewReader("\n123 中")), "line 2: invalid code \"123\"")
+	a.EqualError(Load(strings.NewReader(UnknownGroup+" 中")), "line 1: invalid code \"0000\"")
+	a.EqualError(Load(strings.NewReader("0022")), "line 1: code 0022 has no characters")
+	a.EqualError(Load(strings.NewReader("0022 中文")), "line 1: expected a single character, got \"中文\"")
+	a.Equal("中", FromGroups("0022"))
+}
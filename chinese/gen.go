@@ -0,0 +1,156 @@
+//go:build ignore
+
+// Generates codebook.txt from the kTaiwanTelegraph and kMainlandTelegraph fields
+// of the Unicode Han Database (Unihan), see https://www.unicode.org/reports/tr38/
+//
+//	go run gen.go -o codebook.txt
+//
+// By default the latest Unihan.zip is downloaded from unicode.org, or a local
+// copy of the zip (or of Unihan_OtherMappings.txt) can be given with -unihan
+package main
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const unihanURL = "https://www.unicode.org/Public/UCD/latest/ucd/Unihan.zip"
+
+// The file of Unihan.zip that has the telegraph codes
+const mappingsFile = "Unihan_OtherMappings.txt"
+
+func main() {
+	unihan := flag.String("unihan", "", "path of Unihan.zip or "+mappingsFile+" (default: download "+unihanURL+")")
+	out := flag.String("o", "codebook.txt", "output file")
+	flag.Parse()
+
+	mappings, err := readMappings(*unihan)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// The characters of each code, from Taiwan (traditional) and the mainland (simplified)
+	taiwan, mainland := make(map[string][]rune), make(map[string][]rune)
+	scanner := bufio.NewScanner(bytes.NewReader(mappings))
+	for scanner.Scan() {
+		// e.g. U+4E2D	kTaiwanTelegraph	0022
+		fields := strings.Split(scanner.Text(), "\t")
+		if len(fields) != 3 || !strings.HasPrefix(fields[0], "U+") {
+			continue
+		}
+		var codes map[string][]rune
+		switch fields[1] {
+		case "kTaiwanTelegraph":
+			codes = taiwan
+		case "kMainlandTelegraph":
+			codes = mainland
+		default:
+			continue
+		}
+		r, err := strconv.ParseUint(fields[0][2:], 16, 32)
+		if err != nil {
+			log.Fatalf("invalid code point %q: %v", fields[0], err)
+		}
+		for _, code := range strings.Fields(fields[2]) {
+			codes[code] = append(codes[code], rune(r))
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		log.Fatal(err)
+	}
+
+	var codes []string
+	for code := range taiwan {
+		codes = append(codes, code)
+	}
+	for code := range mainland {
+		if _, ok := taiwan[code]; !ok {
+			codes = append(codes, code)
+		}
+	}
+	sort.Strings(codes)
+
+	w := bytes.Buffer{}
+	fmt.Fprintf(&w, "# Code generated by gen.go from the kTaiwanTelegraph and kMainlandTelegraph\n")
+	fmt.Fprintf(&w, "# fields of the Unicode Han Database (%s). DO NOT EDIT.\n", unihanURL)
+	for _, code := range codes {
+		// The traditional character is decoded, and the simplified variants are only encoded
+		hanzi := sortedRunes(taiwan[code])
+		for _, r := range sortedRunes(mainland[code]) {
+			if !containsRune(hanzi, r) {
+				hanzi = append(hanzi, r)
+			}
+		}
+		w.WriteString(code)
+		for _, r := range hanzi {
+			w.WriteRune(' ')
+			w.WriteRune(r)
+		}
+		w.WriteRune('\n')
+	}
+	if err = os.WriteFile(*out, w.Bytes(), 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// readMappings returns the contents of Unihan_OtherMappings.txt
+func readMappings(unihan string) ([]byte, error) {
+	var data []byte
+	var err error
+	if unihan == "" {
+		var resp *http.Response
+		if resp, err = http.Get(unihanURL); err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("GET %s: %s", unihanURL, resp.Status)
+		}
+		data, err = io.ReadAll(resp.Body)
+	} else {
+		data, err = os.ReadFile(unihan)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if path.Ext(unihan) == ".txt" {
+		return data, nil
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+	f, err := zr.Open(mappingsFile)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return io.ReadAll(f)
+}
+
+func sortedRunes(rs []rune) []rune {
+	rs = append([]rune(nil), rs...)
+	sort.Slice(rs, func(i, j int) bool { return rs[i] < rs[j] })
+	return rs
+}
+
+func containsRune(rs []rune, r rune) bool {
+	for _, other := range rs {
+		if other == r {
+			return true
+		}
+	}
+	return false
+}
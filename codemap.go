@@ -0,0 +1,84 @@
+package morse
+
+// packedCode is a compact representation of a Code made of only Dits and Dahs
+// (separated by SignalSpaces), for use as a map key without allocating. The top
+// byte is the number of dits and dahs, and the rest is a bitmask where a Dah is 1
+type packedCode uint64
+
+// The maximum number of dits and dahs in a packedCode
+const maxPackedSignals = 56
+
+// packCode packs the code into a packedCode, returning false
+// if it can't be packed (e.g. it contains a RuneSpace)
+func packCode(c Code) (packedCode, bool) {
+	// The code must be audible signals separated by signal spaces
+	if len(c)%2 == 0 || len(c) > maxPackedSignals*2-1 {
+		return 0, false
+	}
+	var mask uint64
+	for i := 0; i < len(c); i += 2 {
+		switch c[i] {
+		case Dit:
+		case Dah:
+			mask |= 1 << (i / 2)
+		default:
+			return 0, false
+		}
+		if i+1 < len(c) && c[i+1] != SignalSpace {
+			return 0, false
+		}
+	}
+	return packedCode(uint64(len(c)/2+1)<<maxPackedSignals | mask), true
+}
+
+// codeMap maps codes to values. Codes that can be packed (see packCode) are keyed
+// by their packedCode, and others by their signals, so neither allocates on lookup
+type codeMap[V any] struct {
+	packed map[packedCode]V
+	other  map[string]V
+}
+
+func newCodeMap[V any](size int) codeMap[V] {
+	return codeMap[V]{packed: make(map[packedCode]V, size), other: make(map[string]V)}
+}
+
+func (m codeMap[V]) get(c Code) (v V, ok bool) {
+	if p, packed := packCode(c); packed {
+		v, ok = m.packed[p]
+	} else {
+		// The compiler doesn't allocate for the conversion in a map index
+		v, ok = m.other[string(signalArrayToByteArray(c))]
+	}
+	return
+}
+
+func (m codeMap[V]) set(c Code, v V) {
+	if p, packed := packCode(c); packed {
+		m.packed[p] = v
+	} else {
+		m.other[c.key()] = v
+	}
+}
+
+func (m codeMap[V]) delete(c Code) {
+	if p, packed := packCode(c); packed {
+		delete(m.packed, p)
+	} else {
+		delete(m.other, c.key())
+	}
+}
+
+func (m codeMap[V]) len() int {
+	return len(m.packed) + len(m.other)
+}
+
+func (m codeMap[V]) clone() codeMap[V] {
+	clone := codeMap[V]{packed: make(map[packedCode]V, len(m.packed)), other: make(map[string]V, len(m.other))}
+	for p, v := range m.packed {
+		clone.packed[p] = v
+	}
+	for k, v := range m.other {
+		clone.other[k] = v
+	}
+	return clone
+}
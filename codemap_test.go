@@ -0,0 +1,64 @@
+package morse
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestPackCode(t *testing.T) {
+	a := assert.New(t)
+
+	p, ok := packCode(E)
+	a.True(ok)
+	a.Equal(packedCode(1<<maxPackedSignals), p)
+	p, ok = packCode(A)
+	a.True(ok)
+	a.Equal(packedCode(2<<maxPackedSignals|0b10), p)
+	p, ok = packCode(JoinSignals(S, O, S))
+	a.True(ok)
+	a.Equal(packedCode(9<<maxPackedSignals|0b000111000), p)
+
+	// Codes with the same dits and dahs but different lengths are different
+	p1, _ := packCode(FromCodeString("・・"))
+	p2, _ := packCode(FromCodeString("・・・"))
+	a.NotEqual(p1, p2)
+
+	for _, c := range []Code{{}, JoinLetters(A, B), {Dit, Dah}, {Dit, SignalSpace}, {LongDah}, americanCode(". .")} {
+		_, ok = packCode(c)
+		a.False(ok, c.String())
+	}
+}
+
+func TestCodeMap(t *testing.T) {
+	a := assert.New(t)
+
+	m := newCodeMap[string](0)
+	m.set(A, "a")
+	m.set(americanCode(". ."), "o")
+	a.Equal(2, m.len())
+	v, ok := m.get(A)
+	a.True(ok)
+	a.Equal("a", v)
+	v, ok = m.get(americanCode(". ."))
+	a.True(ok)
+	a.Equal("o", v)
+	_, ok = m.get(N)
+	a.False(ok)
+
+	clone := m.clone()
+	m.delete(A)
+	m.delete(americanCode(". ."))
+	a.Equal(0, m.len())
+	a.Equal(2, clone.len())
+}
+
+func TestDictionary_TextFromCodeAllocs(t *testing.T) {
+	a := assert.New(t)
+
+	for _, c := range []Code{A, JoinSignals(S, O, S), americanCode(".. .")} {
+		a.Zero(testing.AllocsPerRun(100, func() {
+			American.textFromCode(c)
+			DefaultDictionary.textFromCode(c)
+		}), c.String())
+	}
+}
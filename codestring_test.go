@@ -100,3 +100,36 @@ func BenchmarkCodeStringReader5(b *testing.B) {
 func BenchmarkCodeStringReader10(b *testing.B) {
 	benchmarkCodeStringReader(b, 10)
 }
+
+// Benchmark looking up every code of the default dictionary
+
+func benchmarkCodeLookup(b *testing.B, lookup func(c Code)) {
+	var codes []Code
+	for _, entry := range DefaultDictionary.Entries() {
+		codes = append(codes, entry.Code)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, c := range codes {
+			lookup(c)
+		}
+	}
+}
+
+func BenchmarkCodeLookupPacked(b *testing.B) {
+	benchmarkCodeLookup(b, func(c Code) {
+		DefaultDictionary.textFromCode(c)
+	})
+}
+
+func BenchmarkCodeLookupString(b *testing.B) {
+	// How lookup used to work, keyed by the code's string
+	m := make(map[string]string)
+	for _, entry := range DefaultDictionary.Entries() {
+		m[entry.Code.String()] = entry.Text
+	}
+	benchmarkCodeLookup(b, func(c Code) {
+		_ = m[c.String()]
+	})
+}
@@ -2,28 +2,145 @@ package morse
 
 import (
 	"bytes"
+	"fmt"
 	"github.com/bhollier/morse/internal/buffer"
 	"io"
+	"strings"
 	"unicode"
-	"unicode/utf8"
 )
 
-// Decoder converts Morse Code into human-readable text
+// Decoder converts Morse Code into human-readable text, a word at a time.
+// For live sources, see StreamDecoder
 type Decoder struct {
+	charDecoder
 	morseWordScanner Scanner
 	overflow         buffer.Overflow[byte]
 	started          bool
+	// Buffers reused between words, to avoid allocating
+	word        bytes.Buffer
+	segment     bytes.Buffer
+	codeRuneBuf Code
+	// The number of signals scanned, and the offset of the current word
+	scanned    int
+	wordOffset int
+	// The error returned when a code isn't known in strict mode
+	err error
+}
+
+// charDecoder decodes the code of single characters, keeping track of shifts.
+// It is shared by Decoder and StreamDecoder
+type charDecoder struct {
+	opts DecoderOptions
+	// The Shift the decoder is currently in, or nil if
+	// it's using the options' Dictionary
+	shift *Shift
+	// Maps the code of each of the options' prosigns to its markup
+	prosigns codeMap[string]
+	stats    DecodeStats
+}
+
+func newCharDecoder(opts DecoderOptions) charDecoder {
+	if opts.Dictionary == nil {
+		opts.Dictionary = DefaultDictionary
+	}
+	prosigns := newCodeMap[string](len(opts.Prosigns))
+	for name, c := range opts.Prosigns {
+		prosigns.set(c, "<"+strings.ToUpper(name)+">")
+	}
+	return charDecoder{opts: opts, prosigns: prosigns}
+}
+
+// DecoderOptions configures how a Decoder converts Morse Code into text.
+// The zero value is the default configuration
+type DecoderOptions struct {
+	// Dictionary is used to look up the rune of each code.
+	// If nil, DefaultDictionary is used
+	Dictionary *Dictionary
+
+	// Prosigns maps the names of prosigns to their code. A character that
+	// matches one of the codes is decoded as the (uppercase) name in angle
+	// brackets, e.g. "<AR>". See the prosign package for common prosigns
+	Prosigns map[string]Code
+
+	// PreferCharacters determines what a code that is both a prosign and
+	// a character in the Dictionary is decoded as (e.g. AR and '+').
+	// By default it is decoded as the prosign
+	PreferCharacters bool
+
+	// Strict makes Read return a *DecodeError when a code isn't in the
+	// Dictionary, rather than decoding it as '?' (which is ambiguous with
+	// a real question mark). The text of the word before the code is discarded
+	Strict bool
+}
+
+// DecodeError is returned by a strict Decoder or StreamDecoder
+// (see DecoderOptions.Strict) when a code isn't in the Dictionary
+type DecodeError struct {
+	// Code is the code that wasn't in the Dictionary
+	Code Code
+	// Word is the (0-based) index of the word the code is in
+	Word int
+	// Character is the (0-based) index of the code in the word
+	Character int
+	// Offset is the number of signals before the code in the input
+	Offset int
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("morse: unknown code %s at word %d, character %d (signal offset %d)",
+		e.Code, e.Word, e.Character, e.Offset)
+}
+
+// DecodeStats counts what a Decoder or StreamDecoder has decoded so far
+type DecodeStats struct {
+	// Words is the number of words decoded
+	Words int
+	// Characters is the number of characters decoded, including
+	// unknown characters, but not including shifts
+	Characters int
+	// Unknown is the number of characters that weren't in the
+	// Dictionary, and so were decoded as '?' (or caused an error)
+	Unknown int
+}
+
+// Stats returns the counts of what has been decoded so far, e.g. to measure the
+// proportion of unknown characters
+func (d *charDecoder) Stats() DecodeStats {
+	return d.stats
 }
 
 // NewDecoder creates a Morse Decoder from the given Reader
 func NewDecoder(r Reader) *Decoder {
-	return NewDecoderFromScanner(NewScanner(r))
+	return NewDecoderWithOptions(r, DecoderOptions{})
+}
+
+// NewDecoderWithOptions creates a Morse Decoder from the given Reader, configured by opts
+func NewDecoderWithOptions(r Reader, opts DecoderOptions) *Decoder {
+	return NewDecoderFromScannerWithOptions(NewScanner(r), opts)
 }
 
 // NewDecoderFromScanner creates a Morse Decoder from the given Scanner
 func NewDecoderFromScanner(s Scanner) *Decoder {
-	s.Split(ScanWords)
-	return &Decoder{morseWordScanner: s}
+	return NewDecoderFromScannerWithOptions(s, DecoderOptions{})
+}
+
+// NewDecoderFromScannerWithOptions creates a Morse Decoder from the given Scanner, configured by opts
+func NewDecoderFromScannerWithOptions(s Scanner, opts DecoderOptions) *Decoder {
+	d := &Decoder{charDecoder: newCharDecoder(opts), morseWordScanner: s}
+	s.Split(d.scanWords)
+	return d
+}
+
+// scanWords wraps ScanWords to keep track of the offset of each word
+func (d *Decoder) scanWords(data []Signal, atEOF bool) (advance int, token []Signal, err error) {
+	advance, token, err = ScanWords(data, atEOF)
+	if token != nil {
+		// The token is a slice of data, so the difference
+		// in capacity is the offset of the token in data
+		d.wordOffset = d.scanned + cap(data) - cap(token)
+	}
+	d.scanned += advance
+	return
 }
 
 func (d *Decoder) Read(b []byte) (n int, err error) {
@@ -31,6 +148,11 @@ func (d *Decoder) Read(b []byte) (n int, err error) {
 	n = d.overflow.Empty(b)
 	b = b[n:]
 
+	if d.err != nil {
+		return n, d.err
+	}
+
+	shifts := d.opts.Dictionary.Shifts()
 	for len(b) > 0 {
 		if !d.morseWordScanner.Scan() {
 			err = d.morseWordScanner.Err()
@@ -40,7 +162,8 @@ func (d *Decoder) Read(b []byte) (n int, err error) {
 			return
 		}
 
-		word := bytes.Buffer{}
+		word := &d.word
+		word.Reset()
 
 		// First, check if we need to add a preceding space
 		// to separate from the previous word
@@ -50,27 +173,48 @@ func (d *Decoder) Read(b []byte) (n int, err error) {
 			d.started = true
 		}
 
+		// The text decoded since the last shift, which is
+		// composed together when the shift changes
+		segment := &d.segment
+		segment.Reset()
+
 		wordCode := d.morseWordScanner.Code()
-		codeRuneBuf := make(Code, 0, len(StandardWordCode))
+		codeRuneBuf := d.codeRuneBuf[:0]
+		character, characterOffset := 0, d.wordOffset
+		d.stats.Words++
 		for i, s := range wordCode {
 			// If the signal isn't a rune
 			if s != RuneSpace {
+				if len(codeRuneBuf) == 0 {
+					characterOffset = d.wordOffset + i
+				}
 				// Add the signal to the buffer
 				codeRuneBuf = append(codeRuneBuf, s)
 			}
 
 			// If the signal is a rune space or this is the end of the word
 			if s == RuneSpace || i+1 == len(wordCode) {
-				r := Dictionary.FromCode(codeRuneBuf)
-				if r == utf8.RuneError {
-					r = '?'
+				if shift, ok := d.shiftFromCode(codeRuneBuf, shifts); ok {
+					d.dictionary().composeTo(word, segment.Bytes())
+					segment.Reset()
+					d.shift = shift
 				} else {
-					r = unicode.ToUpper(r)
+					if !d.writeCharacter(segment, codeRuneBuf) && d.opts.Strict {
+						d.err = &DecodeError{
+							Code:      append(Code(nil), codeRuneBuf...),
+							Word:      d.stats.Words - 1,
+							Character: character,
+							Offset:    characterOffset,
+						}
+						return n, d.err
+					}
+					character++
 				}
-				word.WriteRune(r)
 				codeRuneBuf = codeRuneBuf[:0]
 			}
 		}
+		d.dictionary().composeTo(word, segment.Bytes())
+		d.codeRuneBuf = codeRuneBuf
 
 		// Copy it
 		bytesCopied := d.overflow.Copy(b, word.Bytes())
@@ -81,6 +225,70 @@ func (d *Decoder) Read(b []byte) (n int, err error) {
 	return
 }
 
+// writeCharacter writes the text of the code of a single (non-shift) character,
+// which may be a prosign. If it isn't in the current Dictionary, false is
+// returned, and "?" is written (unless the decoder is strict)
+func (d *charDecoder) writeCharacter(buf *bytes.Buffer, c Code) bool {
+	d.stats.Characters++
+	if markup, ok := d.prosign(c); ok {
+		buf.WriteString(markup)
+		return true
+	}
+	text, ok := d.dictionary().textFromCode(c)
+	if !ok {
+		d.stats.Unknown++
+		if d.opts.Strict {
+			return false
+		}
+		text = "?"
+	}
+	for _, r := range text {
+		buf.WriteRune(unicode.ToUpper(r))
+	}
+	return ok
+}
+
+// dictionary returns the Dictionary currently being used to decode
+func (d *charDecoder) dictionary() *Dictionary {
+	if d.shift != nil {
+		return d.shift.Dictionary
+	}
+	return d.opts.Dictionary
+}
+
+// prosign returns the markup of the prosign with the given code, if there is one
+// and it should be decoded as a prosign (see DecoderOptions.PreferCharacters)
+func (d *charDecoder) prosign(c Code) (string, bool) {
+	if d.prosigns.len() == 0 {
+		return "", false
+	}
+	markup, ok := d.prosigns.get(c)
+	if !ok {
+		return "", false
+	}
+	if d.opts.PreferCharacters {
+		if _, isCharacter := d.dictionary().textFromCode(c); isCharacter {
+			return "", false
+		}
+	}
+	return markup, true
+}
+
+// shiftFromCode checks whether the code is a shift in or out of a Dictionary,
+// returning the Shift that the decoder should switch to (or nil if it
+// should return to the options' Dictionary)
+func (d *charDecoder) shiftFromCode(c Code, shifts []Shift) (*Shift, bool) {
+	if d.shift != nil {
+		return nil, c.Equal(d.shift.Out)
+	}
+	for i := range shifts {
+		if c.Equal(shifts[i].In) {
+			return &shifts[i], true
+		}
+	}
+	return nil, false
+}
+
 // Decode returns the human-readable text of the given code
 func Decode(code Code) string {
 	d := NewDecoder(NewReader(code))
@@ -91,3 +299,15 @@ func Decode(code Code) string {
 	}
 	return string(b)
 }
+
+// DecodeWithDictionary returns the human-readable text of the given code,
+// using the given Dictionary
+func DecodeWithDictionary(code Code, dict *Dictionary) string {
+	d := NewDecoderWithOptions(NewReader(code), DecoderOptions{Dictionary: dict})
+	b, err := io.ReadAll(d)
+	if err != nil {
+		// panic on error as neither CodeReader or Decoder should ever error
+		panic(err)
+	}
+	return string(b)
+}
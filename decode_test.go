@@ -36,6 +36,86 @@ func TestDecode(t *testing.T) {
 	a.Equal(io.EOF, err)
 }
 
+func TestNewDecoderWithOptions(t *testing.T) {
+	a := assert.New(t)
+
+	d := NewDictionary()
+	d.Add('x', A)
+
+	b, err := io.ReadAll(NewDecoderWithOptions(NewReader(JoinLetters(A, B)), DecoderOptions{Dictionary: d}))
+	a.NoError(err)
+	a.Equal("X?", string(b))
+
+	// The default dictionary should be unaffected
+	a.Equal("AB", Decode(JoinLetters(A, B)))
+}
+
+func TestDecoder_Prosigns(t *testing.T) {
+	a := assert.New(t)
+
+	opts := DecoderOptions{Prosigns: map[string]Code{
+		"ar": JoinSignals(A, R),
+		"SK": JoinSignals(S, K),
+		"AA": JoinSignals(A, A),
+	}}
+
+	code := JoinWords(JoinLetters(T, E, S, T, JoinSignals(A, R)), JoinSignals(S, K))
+	b, err := io.ReadAll(NewDecoderWithOptions(NewReader(code), opts))
+	a.NoError(err)
+	a.Equal("TEST<AR> <SK>", string(b))
+
+	// Without the prosigns, AR is the same as + and SK is unknown
+	a.Equal("TEST+ ?", Decode(code))
+
+	// A code that is also a character is decoded as the prosign, unless characters are preferred
+	dict := DefaultDictionary.Clone()
+	dict.Add('\n', JoinSignals(A, A))
+	opts.Dictionary = dict
+	b, err = io.ReadAll(NewDecoderWithOptions(NewReader(JoinLetters(A, JoinSignals(A, A))), opts))
+	a.NoError(err)
+	a.Equal("A<AA>", string(b))
+
+	opts.PreferCharacters = true
+	b, err = io.ReadAll(NewDecoderWithOptions(NewReader(JoinLetters(A, JoinSignals(A, A))), opts))
+	a.NoError(err)
+	a.Equal("A\n", string(b))
+}
+
+func TestDecoder_Strict(t *testing.T) {
+	a := assert.New(t)
+
+	// SK starts at signal 16 (HI is 11 signals, then the word space and A)
+	code := JoinWords(JoinLetters(H, I), JoinLetters(A, JoinSignals(S, K), B))
+
+	d := NewDecoderWithOptions(NewReader(code), DecoderOptions{Strict: true})
+	b, err := io.ReadAll(d)
+	a.Equal("HI", string(b))
+	var decodeErr *DecodeError
+	if a.ErrorAs(err, &decodeErr) {
+		a.Equal(&DecodeError{Code: JoinSignals(S, K), Word: 1, Character: 1, Offset: 16}, decodeErr)
+		a.Equal("morse: unknown code ・・・－・－ at word 1, character 1 (signal offset 16)", err.Error())
+	}
+	a.Equal(DecodeStats{Words: 2, Characters: 4, Unknown: 1}, d.Stats())
+
+	// The error is sticky
+	_, err = d.Read(make([]byte, 8))
+	a.ErrorAs(err, &decodeErr)
+
+	// Otherwise, unknown codes are counted
+	d = NewDecoder(NewReader(code))
+	b, err = io.ReadAll(d)
+	a.NoError(err)
+	a.Equal("HI A?B", string(b))
+	a.Equal(DecodeStats{Words: 2, Characters: 5, Unknown: 1}, d.Stats())
+
+	// Shifts aren't counted as characters
+	d = NewDecoderWithOptions(NewReader(JoinLetters(WabunIn, FromCodeString("・－"), WabunOut)), DecoderOptions{Strict: true})
+	b, err = io.ReadAll(d)
+	a.NoError(err)
+	a.Equal("イ", string(b))
+	a.Equal(DecodeStats{Words: 1, Characters: 1}, d.Stats())
+}
+
 const benchmarkDecoderSeed = 42
 const benchmarkDecoderBufferSize = 512
 
@@ -45,6 +125,7 @@ func benchmarkDecoder(b *testing.B, paragraphs int) {
 	// Convert the text into morse code
 	code := FromText(text)
 
+	b.ReportAllocs()
 	benchmarkReader(b, benchmarkDecoderBufferSize, func() genericReader[byte] {
 		return NewDecoder(NewReader(code))
 	})
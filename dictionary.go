@@ -1,42 +1,411 @@
 package morse
 
 import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
 	"unicode"
 	"unicode/utf8"
 )
 
-type dictionary struct {
+// Dictionary is a two-way mapping between human-readable runes and Morse Code.
+// It is safe to modify a Dictionary while it is being used by a TextEncoder
+// or Decoder in another goroutine
+type Dictionary struct {
+	mu          sync.RWMutex
 	runeCodeMap map[rune]Code
-	codeRuneMap map[string]rune
+	// Entries of more than one rune (e.g. the German "CH"), which
+	// are matched before the runes when encoding
+	textCodeMap map[string]Code
+	// The length (in runes) of the longest key in textCodeMap
+	maxTextLen  int
+	codeTextMap codeMap[string]
+	shifts      []Shift
+	composer    Composer
 }
 
-var Dictionary = dictionary{
-	runeCodeMap: make(map[rune]Code),
-	codeRuneMap: make(map[string]rune),
+// Shift describes switching from one Dictionary into another part way
+// through a message, e.g. from Latin into Wabun (Japanese) with the
+// DO prosign, and back out again with SN
+type Shift struct {
+	// Dictionary is the Dictionary that is shifted into
+	Dictionary *Dictionary
+	// In is the code sent to shift into Dictionary
+	In Code
+	// Out is the code sent to shift back out of Dictionary
+	Out Code
+}
+
+// Composer converts between the way text is written and the runes that are
+// actually sent in Morse, e.g. a kana with a dakuten is sent as the kana followed
+// by the dakuten sign. Decompose is used when encoding, and Compose when decoding
+type Composer interface {
+	// Decompose converts written text into the runes that are sent
+	Decompose(s string) string
+	// Compose converts the runes that were received back into written text
+	Compose(s string) string
+}
+
+// NewDictionary creates an empty Dictionary
+func NewDictionary() *Dictionary {
+	return &Dictionary{
+		runeCodeMap: make(map[rune]Code),
+		textCodeMap: make(map[string]Code),
+		codeTextMap: newCodeMap[string](0),
+	}
+}
+
+// DefaultDictionary is the Dictionary used by TextEncoder and Decoder when one
+// isn't given, containing the International Morse Code characters.
+// Use Clone to create a modifiable copy that doesn't affect other users
+var DefaultDictionary = NewDictionary()
+
+// Clone returns a copy of the dictionary, which can be modified without affecting d
+func (d *Dictionary) Clone() *Dictionary {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	clone := &Dictionary{
+		runeCodeMap: make(map[rune]Code, len(d.runeCodeMap)),
+		textCodeMap: make(map[string]Code, len(d.textCodeMap)),
+		maxTextLen:  d.maxTextLen,
+		codeTextMap: d.codeTextMap.clone(),
+	}
+	for r, c := range d.runeCodeMap {
+		clone.runeCodeMap[r] = c
+	}
+	for t, c := range d.textCodeMap {
+		clone.textCodeMap[t] = c
+	}
+	// The shifted dictionaries themselves aren't cloned
+	clone.shifts = append(clone.shifts, d.shifts...)
+	clone.composer = d.composer
+	return clone
 }
 
 // Add an entry for linking the rune r with the morse code c
-func (d *dictionary) Add(r rune, c Code) {
-	d.runeCodeMap[r] = c
-	d.codeRuneMap[c.String()] = r
+func (d *Dictionary) Add(r rune, c Code) {
+	d.addText(string(r), c, true)
+}
+
+// AddText adds an entry for linking the text (of one or more runes, e.g. a
+// digraph or abbreviation) with the morse code c. When encoding, the longest
+// entry that matches the text is used, so "CH" is sent as one code if there's
+// an entry for it, rather than as C then H
+func (d *Dictionary) AddText(text string, c Code) {
+	d.addText(text, c, true)
+}
+
+// addText adds an entry for linking the text (of one or more runes)
+// with the morse code c. If decode is false, the text is only used
+// when encoding, e.g. for letters that are sent the same as another
+func (d *Dictionary) addText(text string, c Code, decode bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	key := strings.ToLower(text)
+	if n := utf8.RuneCountInString(key); n == 1 {
+		r, _ := utf8.DecodeRuneInString(key)
+		d.runeCodeMap[r] = c
+	} else {
+		d.textCodeMap[key] = c
+		if n > d.maxTextLen {
+			d.maxTextLen = n
+		}
+	}
+	if decode {
+		d.codeTextMap.set(c, text)
+	}
+}
+
+// AddStrict is like Add, but returns a *ConflictError (and doesn't add
+// the entry) if the rune or the code are already in the dictionary
+func (d *Dictionary) AddStrict(r rune, c Code) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	key := string(unicode.ToLower(r))
+	if existing, ok := d.entryCode(key); ok {
+		return &ConflictError{Text: string(r), Code: c, ExistingText: key, ExistingCode: existing}
+	}
+	if existing, ok := d.codeTextMap.get(c); ok {
+		return &ConflictError{Text: string(r), Code: c, ExistingText: existing, ExistingCode: c}
+	}
+	d.runeCodeMap[unicode.ToLower(r)] = c
+	d.codeTextMap.set(c, string(r))
+	return nil
+}
+
+// ConflictError is returned by Dictionary.AddStrict when
+// the rune or the code are already in the dictionary
+type ConflictError struct {
+	// Text and Code are the entry that couldn't be added
+	Text string
+	Code Code
+	// ExistingText and ExistingCode are the entry already in the dictionary
+	ExistingText string
+	ExistingCode Code
+}
+
+func (e *ConflictError) Error() string {
+	if strings.EqualFold(e.Text, e.ExistingText) {
+		return fmt.Sprintf("morse: %q is already in the dictionary as %s", e.Text, e.ExistingCode)
+	}
+	return fmt.Sprintf("morse: %s (for %q) is already in the dictionary as %q", e.Code, e.Text, e.ExistingText)
+}
+
+// Remove removes the entry for the text (of one or more runes), returning
+// false if there isn't one. If the entry's code was decoded as the text,
+// the code is no longer decoded (even if another entry has the same code)
+func (d *Dictionary) Remove(text string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	key := strings.ToLower(text)
+	c, ok := d.entryCode(key)
+	if !ok {
+		return false
+	}
+	if utf8.RuneCountInString(key) == 1 {
+		r, _ := utf8.DecodeRuneInString(key)
+		delete(d.runeCodeMap, r)
+	} else {
+		delete(d.textCodeMap, key)
+	}
+	if decoded, ok := d.codeTextMap.get(c); ok && strings.ToLower(decoded) == key {
+		d.codeTextMap.delete(c)
+	}
+	return true
+}
+
+// entryCode returns the code of the entry with the given (lowercase) text.
+// d.mu must be held
+func (d *Dictionary) entryCode(key string) (Code, bool) {
+	if utf8.RuneCountInString(key) == 1 {
+		r, _ := utf8.DecodeRuneInString(key)
+		c, ok := d.runeCodeMap[r]
+		return c, ok
+	}
+	c, ok := d.textCodeMap[key]
+	return c, ok
+}
+
+// Entry is a single entry of a Dictionary, see Dictionary.Entries
+type Entry struct {
+	// Text is the (lowercase) text of the entry, which may be more than one rune
+	Text string
+	Code Code
+	// EncodeOnly is whether the entry is only used when encoding,
+	// as its code is decoded as another entry (or not at all)
+	EncodeOnly bool
+}
+
+// Entries returns the entries of the dictionary (not including its shifts), sorted by text
+func (d *Dictionary) Entries() []Entry {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	entries := make([]Entry, 0, len(d.runeCodeMap)+len(d.textCodeMap))
+	add := func(key string, c Code) {
+		decoded, ok := d.codeTextMap.get(c)
+		entries = append(entries, Entry{
+			Text:       key,
+			Code:       c,
+			EncodeOnly: !ok || strings.ToLower(decoded) != key,
+		})
+	}
+	for r, c := range d.runeCodeMap {
+		add(string(r), c)
+	}
+	for t, c := range d.textCodeMap {
+		add(t, c)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Text < entries[j].Text
+	})
+	return entries
+}
+
+// Conflict is a code shared by more than one entry of a Dictionary, see Dictionary.Conflicts
+type Conflict struct {
+	Code Code
+	// Entries are the entries with the code, where the
+	// entry the code is decoded as (if any) is first
+	Entries []Entry
+}
+
+// Conflicts returns the codes that are shared by more than one entry of the
+// dictionary, e.g. when a rune is sent the same as another. Only one of the
+// entries can be decoded, so the others are only used when encoding
+func (d *Dictionary) Conflicts() []Conflict {
+	byCode := make(map[string]*Conflict)
+	var keys []string
+	for _, entry := range d.Entries() {
+		conflict, ok := byCode[entry.Code.key()]
+		if !ok {
+			conflict = &Conflict{Code: entry.Code}
+			byCode[entry.Code.key()] = conflict
+			keys = append(keys, entry.Code.key())
+		}
+		// The entries are already sorted by text, so keep that order after the decoded one
+		if !entry.EncodeOnly {
+			conflict.Entries = append([]Entry{entry}, conflict.Entries...)
+		} else {
+			conflict.Entries = append(conflict.Entries, entry)
+		}
+	}
+
+	sort.Strings(keys)
+	var conflicts []Conflict
+	for _, k := range keys {
+		if conflict := byCode[k]; len(conflict.Entries) > 1 {
+			conflicts = append(conflicts, *conflict)
+		}
+	}
+	return conflicts
 }
 
 // AddCodeString is a wrapper around Add which calls FromCodeString on codeStr
-func (d *dictionary) AddCodeString(r rune, codeStr string) {
+func (d *Dictionary) AddCodeString(r rune, codeStr string) {
 	d.Add(r, FromCodeString(codeStr))
 }
 
+// AddTextCodeString is a wrapper around AddText which calls FromCodeString on codeStr
+func (d *Dictionary) AddTextCodeString(text string, codeStr string) {
+	d.AddText(text, FromCodeString(codeStr))
+}
+
+// AddShift adds a Shift into another Dictionary. When encoding, runes that aren't in d
+// but are in s.Dictionary are sent after s.In, and when decoding, s.In switches to
+// s.Dictionary until s.Out is received
+func (d *Dictionary) AddShift(s Shift) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.shifts = append(d.shifts, s)
+}
+
+// Shifts returns the shifts that have been added to the dictionary
+func (d *Dictionary) Shifts() []Shift {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return append([]Shift(nil), d.shifts...)
+}
+
+// SetComposer sets the Composer used when encoding and decoding text with the
+// dictionary, or nil to use the text as-is
+func (d *Dictionary) SetComposer(c Composer) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.composer = c
+}
+
+// Decompose converts written text into the runes that are sent in Morse,
+// using the dictionary's Composer. See SetComposer
+func (d *Dictionary) Decompose(s string) string {
+	d.mu.RLock()
+	c := d.composer
+	d.mu.RUnlock()
+	if c == nil {
+		return s
+	}
+	return c.Decompose(s)
+}
+
+// Compose converts the runes received in Morse back into written text,
+// using the dictionary's Composer. See SetComposer
+func (d *Dictionary) Compose(s string) string {
+	d.mu.RLock()
+	c := d.composer
+	d.mu.RUnlock()
+	if c == nil {
+		return s
+	}
+	return c.Compose(s)
+}
+
+// composes returns whether the dictionary has a Composer
+func (d *Dictionary) composes() bool {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.composer != nil
+}
+
+// composeTo writes the composed text of the runes to buf, without
+// converting them to a string if the dictionary has no Composer
+func (d *Dictionary) composeTo(buf *bytes.Buffer, runes []byte) {
+	d.mu.RLock()
+	c := d.composer
+	d.mu.RUnlock()
+	if c == nil {
+		buf.Write(runes)
+		return
+	}
+	buf.WriteString(c.Compose(string(runes)))
+}
+
 // FromRune returns the Morse code of the given human-readable rune, or nil if unknown
-func (d *dictionary) FromRune(r rune) Code {
+func (d *Dictionary) FromRune(r rune) Code {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
 	c, _ := d.runeCodeMap[unicode.ToLower(r)]
 	return c
 }
 
-// FromCode returns the human-readable rune of the given Morse code, or utf8.RuneError if unknown
-func (d *dictionary) FromCode(c Code) rune {
-	r, ok := d.codeRuneMap[c.String()]
-	if !ok {
+// FromText returns the Morse code of the entry for the given human-readable
+// text (which may be a single rune), or nil if unknown
+func (d *Dictionary) FromText(text string) Code {
+	if c, n := d.match([]rune(text)); n == utf8.RuneCountInString(text) {
+		return c
+	}
+	return nil
+}
+
+// match returns the Morse code of the longest entry at the start of
+// the given runes, and the number of runes in the entry (or 0 if unknown)
+func (d *Dictionary) match(rs []rune) (Code, int) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	for n := intMin(d.maxTextLen, len(rs)); n > 1; n-- {
+		if c, ok := d.textCodeMap[strings.ToLower(string(rs[:n]))]; ok {
+			return c, n
+		}
+	}
+	if len(rs) > 0 {
+		if c, ok := d.runeCodeMap[unicode.ToLower(rs[0])]; ok {
+			return c, 1
+		}
+	}
+	return nil, 0
+}
+
+// FromCode returns the human-readable rune of the given Morse code, or
+// utf8.RuneError if unknown (or if the code is for more than one rune)
+func (d *Dictionary) FromCode(c Code) rune {
+	text, ok := d.textFromCode(c)
+	if !ok || utf8.RuneCountInString(text) != 1 {
 		return utf8.RuneError
 	}
+	r, _ := utf8.DecodeRuneInString(text)
 	return r
 }
+
+// TextFromCode returns the human-readable text of the given Morse code,
+// which may be more than one rune, or an empty string if unknown
+func (d *Dictionary) TextFromCode(c Code) string {
+	text, _ := d.textFromCode(c)
+	return text
+}
+
+// textFromCode returns the human-readable text of the given Morse code,
+// which may be more than one rune
+func (d *Dictionary) textFromCode(c Code) (string, bool) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	text, ok := d.codeTextMap.get(c)
+	return text, ok
+}
+
+func intMin(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,150 @@
+package morse
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"sync"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestDictionary_Clone(t *testing.T) {
+	a := assert.New(t)
+
+	d := DefaultDictionary.Clone()
+	a.Equal(A.String(), d.FromRune('a').String())
+	a.Equal('a', d.FromCode(A))
+
+	// Modifying the clone shouldn't affect the original
+	d.Add('a', B)
+	a.Equal(B.String(), d.FromRune('a').String())
+	a.Equal(A.String(), DefaultDictionary.FromRune('a').String())
+
+	a.Nil(NewDictionary().FromRune('a'))
+	a.Equal(utf8.RuneError, NewDictionary().FromCode(A))
+}
+
+func TestDictionary_Concurrent(t *testing.T) {
+	a := assert.New(t)
+
+	d := DefaultDictionary.Clone()
+	wg := sync.WaitGroup{}
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			d.Add('ä', FromCodeString(".-.-"))
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			c, err := ReadAll(ReaderFromTextWithOptions(strings.NewReader("sos"),
+				EncoderOptions{Dictionary: d}))
+			a.NoError(err)
+			a.Equal(FromText("sos").String(), Code(c).String())
+		}
+	}()
+	wg.Wait()
+}
+
+func TestDictionary_AddText(t *testing.T) {
+	a := assert.New(t)
+
+	d := DefaultDictionary.Clone()
+	d.AddText("sos", JoinSignals(S, O, S))
+	d.AddTextCodeString("QTH", "－－・－－・・・・")
+
+	a.Equal(JoinSignals(S, O, S).String(), d.FromText("SOS").String())
+	a.Equal(A.String(), d.FromText("a").String())
+	a.Nil(d.FromText("so"))
+	a.Equal("sos", d.TextFromCode(JoinSignals(S, O, S)))
+	a.Equal(utf8.RuneError, d.FromCode(JoinSignals(S, O, S)))
+	a.Equal("", d.TextFromCode(JoinSignals(S, K)))
+
+	// The longest entry is matched first
+	c, err := FromTextWithOptions("soss sso qth?", EncoderOptions{Dictionary: d})
+	a.NoError(err)
+	a.Equal(JoinWords(
+		JoinLetters(JoinSignals(S, O, S), S),
+		JoinLetters(S, S, O),
+		JoinLetters(FromCodeString("－－・－－・・・・"), QuestionMark)).String(), c.String())
+	a.Equal("SOSS SSO QTH?", DecodeWithDictionary(c, d))
+}
+
+func TestDictionary_Entries(t *testing.T) {
+	a := assert.New(t)
+
+	d := NewDictionary()
+	d.Add('b', B)
+	d.Add('A', A)
+	d.AddText("ch", CH)
+	d.addText("ä", JoinSignals(A, A), false)
+	a.Equal([]Entry{
+		{Text: "a", Code: A},
+		{Text: "b", Code: B},
+		{Text: "ch", Code: CH},
+		{Text: "ä", Code: JoinSignals(A, A), EncodeOnly: true},
+	}, d.Entries())
+
+	a.True(d.Remove("CH"))
+	a.False(d.Remove("ch"))
+	a.True(d.Remove("a"))
+	a.Nil(d.FromText("ch"))
+	a.Equal("", d.TextFromCode(A))
+	a.Equal([]Entry{
+		{Text: "b", Code: B},
+		{Text: "ä", Code: JoinSignals(A, A), EncodeOnly: true},
+	}, d.Entries())
+}
+
+func TestDictionary_Conflicts(t *testing.T) {
+	a := assert.New(t)
+
+	d := NewDictionary()
+	d.Add('e', E)
+	d.Add('ë', E)
+	d.Add('ė', E)
+	d.Add('t', T)
+	d.addText("ä", JoinSignals(A, A), false)
+	d.addText("æ", JoinSignals(A, A), false)
+	a.Equal([]Conflict{
+		{Code: E, Entries: []Entry{
+			{Text: "ė", Code: E},
+			{Text: "e", Code: E, EncodeOnly: true},
+			{Text: "ë", Code: E, EncodeOnly: true},
+		}},
+		{Code: JoinSignals(A, A), Entries: []Entry{
+			{Text: "ä", Code: JoinSignals(A, A), EncodeOnly: true},
+			{Text: "æ", Code: JoinSignals(A, A), EncodeOnly: true},
+		}},
+	}, d.Conflicts())
+
+	// The only ITU character that shares its code is the multiplication sign
+	conflicts := ITUDictionary.Conflicts()
+	if a.Len(conflicts, 1) {
+		a.Equal([]Entry{{Text: "x", Code: X}, {Text: "×", Code: X, EncodeOnly: true}}, conflicts[0].Entries)
+	}
+}
+
+func TestDictionary_AddStrict(t *testing.T) {
+	a := assert.New(t)
+
+	d := NewDictionary()
+	a.NoError(d.AddStrict('a', A))
+	a.NoError(d.AddStrict('b', B))
+
+	var conflictErr *ConflictError
+	if err := d.AddStrict('A', N); a.ErrorAs(err, &conflictErr) {
+		a.Equal("a", conflictErr.ExistingText)
+		a.Equal(A, conflictErr.ExistingCode)
+		a.Equal(`morse: "A" is already in the dictionary as ・－`, err.Error())
+	}
+	if err := d.AddStrict('ä', B); a.ErrorAs(err, &conflictErr) {
+		a.Equal("b", conflictErr.ExistingText)
+		a.Equal(`morse: －・・・ (for "ä") is already in the dictionary as "b"`, err.Error())
+	}
+	a.Equal(A, d.FromRune('a'))
+	a.Nil(d.FromRune('ä'))
+	a.Equal('b', d.FromCode(B))
+}
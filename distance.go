@@ -0,0 +1,190 @@
+package morse
+
+import (
+	"math"
+	"sort"
+	"strings"
+)
+
+// The cost of substituting one audible signal for another (e.g. a dit for a dah)
+// in Distance, compared to inserting or deleting a signal, as mistiming a
+// signal is an easier mistake than missing one out
+const signalSubstituteCost = 0.5
+
+// The cost in Distance of the space before a signal being one step longer or
+// shorter in one code than the other, where the steps are no space, a space within
+// the character (i.e. an InternalSpace) and a space between characters. So a
+// missing RuneSpace costs as much as a missing signal, but mistiming a space is
+// as easy a mistake as mistiming a signal
+const spaceMismatchCost = 0.5
+
+// Distance is the edit distance between the audible signals of the codes, so
+// e.g. the distance between 4 (・・・・－) and V (・・・－) is 1, as is the distance
+// between 6 (－・・・・) and B (－・・・). Inserting or deleting a signal costs 1, and
+// substituting a signal (e.g. a dit for a dah) costs 0.5. A signal that follows a
+// different space in one code than the other costs another 0.5 for a space within
+// the character (e.g. the InternalSpace of American Morse C, ・・ ・, which isn't in
+// S) and 1 for a space between characters (e.g. E T isn't A). The spaces between
+// characters and words are treated the same
+func Distance(a, b Code) float64 {
+	as, bs := distanceSignals(a), distanceSignals(b)
+	prev := make([]float64, len(bs)+1)
+	cur := make([]float64, len(bs)+1)
+	for j := range prev {
+		prev[j] = float64(j)
+	}
+	for i := 1; i <= len(as); i++ {
+		cur[0] = float64(i)
+		for j := 1; j <= len(bs); j++ {
+			sub := prev[j-1]
+			if as[i-1].signal != bs[j-1].signal {
+				sub += signalSubstituteCost
+			}
+			sub += spaceMismatchCost * math.Abs(float64(as[i-1].space-bs[j-1].space))
+			cur[j] = math.Min(math.Min(prev[j]+1, cur[j-1]+1), sub)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(bs)]
+}
+
+// The spaces before a signal in distanceSignal, from shortest to longest
+const (
+	noSpace = iota
+	internalSpace
+	runeSpace
+)
+
+// distanceSignal is an audible signal of a code,
+// and the space before it (e.g. internalSpace)
+type distanceSignal struct {
+	signal Signal
+	space  int
+}
+
+// distanceSignals returns the audible signals of the code
+func distanceSignals(c Code) []distanceSignal {
+	signals := make([]distanceSignal, 0, len(c))
+	space := noSpace
+	for _, s := range c {
+		if s.Audible() {
+			// Spaces before the first signal are ignored
+			if len(signals) == 0 {
+				space = noSpace
+			}
+			signals = append(signals, distanceSignal{signal: s, space: space})
+			space = noSpace
+		} else if d := s.DitDuration(); d >= RuneSpace.DitDuration() {
+			space = runeSpace
+		} else if d > SignalSpace.DitDuration() && space < internalSpace {
+			space = internalSpace
+		}
+	}
+	return signals
+}
+
+// Confusion is a pair of characters and the Distance between their codes
+type Confusion struct {
+	A, B     string
+	Distance float64
+}
+
+// ConfusionMatrix is the Distance between the codes of every pair of
+// characters in a Dictionary, i.e. how easy they are to mix up
+type ConfusionMatrix struct {
+	// Texts are the characters of the matrix, in the order of Distances
+	Texts []string
+	// Distances are the distances between the codes of the characters,
+	// where Distances[i][j] is the distance between Texts[i] and Texts[j]
+	Distances [][]float64
+	index     map[string]int
+}
+
+// NewConfusionMatrix creates a ConfusionMatrix of the characters in the Dictionary
+// that can be decoded (not including its shifts), computed from their codes.
+// Whitespace (e.g. the space between words, or a newline) and entries without
+// audible signals aren't characters that can be confused, so are left out.
+// The matrix is a snapshot, so it isn't updated if the Dictionary is
+func NewConfusionMatrix(d *Dictionary) *ConfusionMatrix {
+	m := &ConfusionMatrix{index: make(map[string]int)}
+	var codes []Code
+	for _, entry := range d.Entries() {
+		if entry.EncodeOnly || len(distanceSignals(entry.Code)) == 0 || strings.TrimSpace(entry.Text) == "" {
+			continue
+		}
+		m.index[entry.Text] = len(m.Texts)
+		m.Texts = append(m.Texts, entry.Text)
+		codes = append(codes, entry.Code)
+	}
+	m.Distances = make([][]float64, len(codes))
+	for i := range codes {
+		m.Distances[i] = make([]float64, len(codes))
+		for j := 0; j < i; j++ {
+			m.Distances[i][j] = Distance(codes[i], codes[j])
+			m.Distances[j][i] = m.Distances[i][j]
+		}
+	}
+	return m
+}
+
+// Distance returns the distance between the codes of the characters,
+// or false if either of them isn't in the matrix
+func (m *ConfusionMatrix) Distance(a, b string) (float64, bool) {
+	i, ok := m.index[a]
+	if !ok {
+		return 0, false
+	}
+	j, ok := m.index[b]
+	if !ok {
+		return 0, false
+	}
+	return m.Distances[i][j], true
+}
+
+// Confusable returns up to n characters (or all, if n is 0) that are
+// the easiest to mix up with the given character, closest first
+func (m *ConfusionMatrix) Confusable(text string, n int) []Confusion {
+	i, ok := m.index[text]
+	if !ok {
+		return nil
+	}
+	confusions := make([]Confusion, 0, len(m.Texts)-1)
+	for j, other := range m.Texts {
+		if j != i {
+			confusions = append(confusions, Confusion{A: text, B: other, Distance: m.Distances[i][j]})
+		}
+	}
+	sortConfusions(confusions)
+	if n > 0 && len(confusions) > n {
+		confusions = confusions[:n]
+	}
+	return confusions
+}
+
+// Pairs returns every pair of characters whose distance is
+// at most maxDistance, closest first
+func (m *ConfusionMatrix) Pairs(maxDistance float64) []Confusion {
+	var confusions []Confusion
+	for i := range m.Texts {
+		for j := i + 1; j < len(m.Texts); j++ {
+			if m.Distances[i][j] <= maxDistance {
+				confusions = append(confusions, Confusion{A: m.Texts[i], B: m.Texts[j], Distance: m.Distances[i][j]})
+			}
+		}
+	}
+	sortConfusions(confusions)
+	return confusions
+}
+
+// sortConfusions sorts by distance, then by the characters
+func sortConfusions(confusions []Confusion) {
+	sort.SliceStable(confusions, func(i, j int) bool {
+		if confusions[i].Distance != confusions[j].Distance {
+			return confusions[i].Distance < confusions[j].Distance
+		}
+		if confusions[i].A != confusions[j].A {
+			return confusions[i].A < confusions[j].A
+		}
+		return confusions[i].B < confusions[j].B
+	})
+}
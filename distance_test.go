@@ -0,0 +1,62 @@
+package morse
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestDistance(t *testing.T) {
+	a := assert.New(t)
+
+	a.Equal(0.0, Distance(S, S))
+	a.Equal(1.0, Distance(Four, V))
+	a.Equal(1.0, Distance(Six, B))
+	a.Equal(0.5, Distance(E, T))
+	a.Equal(Distance(A, N), Distance(N, A))
+	a.Equal(4.0, Distance(Code{}, H))
+
+	// A missing space between characters costs as much as a missing signal, and
+	// the spaces between characters and words are the same
+	a.Equal(1.0, Distance(JoinLetters(E, T), A))
+	a.Equal(1.0, Distance(JoinWords(E, T), A))
+	a.Equal(0.0, Distance(JoinWords(E, T), JoinLetters(E, T)))
+	a.Equal(1.5, Distance(JoinLetters(E, E), A))
+
+	// A space within a character costs half as much, so American C (・・ ・) isn't S (・・・)
+	a.Equal(0.5, Distance(americanCode(".. ."), americanCode("...")))
+	a.Equal(1.0, Distance(americanCode(".. ."), americanCode(". ..")))
+	a.Equal(1.0, Distance(americanCode(". ."), americanCode(".-")))
+	a.Equal(0.0, Distance(americanCode(". .."), americanCode(". ..")))
+}
+
+func TestConfusionMatrix(t *testing.T) {
+	a := assert.New(t)
+
+	m := NewConfusionMatrix(DefaultDictionary)
+	d, ok := m.Distance("4", "v")
+	a.True(ok)
+	a.Equal(1.0, d)
+	d, _ = m.Distance("6", "b")
+	a.Equal(1.0, d)
+	_, ok = m.Distance("4", "×")
+	a.False(ok)
+
+	// Whitespace can't be confused with characters
+	a.NotContains(m.Texts, " ")
+	for _, text := range m.Texts {
+		a.NotEmpty(strings.TrimSpace(text))
+	}
+
+	confusable := m.Confusable("e", 2)
+	a.Len(confusable, 2)
+	a.Equal(Confusion{A: "e", B: "t", Distance: 0.5}, confusable[0])
+
+	// Pairs of characters with a single mistimed signal
+	pairs := m.Pairs(0.5)
+	a.Contains(pairs, Confusion{A: "e", B: "t", Distance: 0.5})
+	for _, p := range pairs {
+		a.LessOrEqual(p.Distance, 0.5)
+		a.Less(p.A, p.B)
+	}
+}
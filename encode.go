@@ -2,30 +2,146 @@ package morse
 
 import (
 	"bufio"
+	"fmt"
 	"github.com/bhollier/morse/internal/buffer"
 	"io"
+	"sort"
 	"strings"
+	"unicode/utf8"
 )
 
-// TextEncoder converts human-readable byte text from an io.Reader into Morse signals
+// TextEncoder converts human-readable byte text from an io.Reader into Morse signals.
+// Prosigns can be written with markup, e.g. "<AR>" or "[SK]", which is sent as the
+// letters of the name run together (see JoinSignals) unless the name is in
+// EncoderOptions.Prosigns
 type TextEncoder struct {
 	wordScanner *bufio.Scanner
 	overflow    buffer.Overflow[Signal]
 	started     bool
+	opts        EncoderOptions
+	// The Shift the encoder is currently in, or nil if
+	// it's using the options' Dictionary
+	shift         *Shift
+	substitutions []Substitution
+	// Maps the (uppercase) names of the options' prosigns to their code
+	prosigns map[string]Code
+	// The number of bytes the word scanner has advanced
+	// by, and the offset of the current word
+	scanned, wordOffset int
+	err                 error
+}
+
+// UnknownRunePolicy determines what a TextEncoder does with a rune that isn't
+// in its dictionaries and can't be transliterated
+type UnknownRunePolicy int
+
+const (
+	// SubstituteUnknown sends EncoderOptions.Replacement instead of the rune
+	SubstituteUnknown UnknownRunePolicy = iota
+	// SkipUnknown sends nothing for the rune
+	SkipUnknown
+	// ErrorOnUnknown causes TextEncoder.Read to return an *UnknownRuneError.
+	// Like the other policies, runes are transliterated first (unless strict),
+	// so the same runes are unknown whatever the policy
+	ErrorOnUnknown
+)
+
+// UnknownRuneError is returned by a TextEncoder using the ErrorOnUnknown policy
+// when it reads a rune it can't encode
+type UnknownRuneError struct {
+	// Rune is the rune that couldn't be encoded
+	Rune rune
+	// Offset is the offset of the rune in the input text, in bytes
+	Offset int
+}
+
+func (e *UnknownRuneError) Error() string {
+	return fmt.Sprintf("morse: unknown rune %q at offset %d", e.Rune, e.Offset)
+}
+
+// EncoderOptions configures how a TextEncoder converts text into Morse Code.
+// The zero value is the default configuration
+type EncoderOptions struct {
+	// Dictionary is used to look up the code of each rune.
+	// If nil, DefaultDictionary is used
+	Dictionary *Dictionary
+
+	// Transliterations maps (lowercase) runes that aren't in the Dictionary to
+	// the text to send instead. These take priority over the built-in
+	// transliterations, see RegisterTransliteration
+	Transliterations map[rune]string
+
+	// Strict disables transliteration, so runes that aren't in the Dictionary
+	// are always handled by UnknownRunes. Use with ITUDictionary to only send
+	// characters that the ITU standard allows (or ErrorOnUnknown to refuse them)
+	Strict bool
+
+	// UnknownRunes is what to do with runes that aren't in the Dictionary
+	// and can't be transliterated. Defaults to SubstituteUnknown
+	UnknownRunes UnknownRunePolicy
+
+	// Replacement is the code sent for unknown runes when using the
+	// SubstituteUnknown policy. If nil, the code for '?' is sent
+	Replacement Code
+
+	// Prosigns maps the names of prosigns to their code, for use in prosign
+	// markup (see TextEncoder). Names are case-insensitive, and take priority
+	// over the letters of the name run together. If names only differ in case,
+	// the uppercase one is used
+	Prosigns map[string]Code
 }
 
 // ReaderFromText creates a TextEncoder that retrieves human-readable text
 // from the given io.Reader and converts it into Morse Code
 func ReaderFromText(r io.Reader) *TextEncoder {
-	return ReaderFromTextScanner(bufio.NewScanner(r))
+	return ReaderFromTextWithOptions(r, EncoderOptions{})
+}
+
+// ReaderFromTextWithOptions creates a TextEncoder that retrieves human-readable text
+// from the given io.Reader and converts it into Morse Code, configured by opts
+func ReaderFromTextWithOptions(r io.Reader, opts EncoderOptions) *TextEncoder {
+	return ReaderFromTextScannerWithOptions(bufio.NewScanner(r), opts)
 }
 
 // ReaderFromTextScanner creates a TextEncoder that retrieves human-readable text
 // from the given bufio.Scanner and converts it into Morse Code
 func ReaderFromTextScanner(s *bufio.Scanner) *TextEncoder {
-	s.Split(bufio.ScanWords) // todo what if the input has no spaces?
+	return ReaderFromTextScannerWithOptions(s, EncoderOptions{})
+}
+
+// ReaderFromTextScannerWithOptions creates a TextEncoder that retrieves human-readable text
+// from the given bufio.Scanner and converts it into Morse Code, configured by opts
+func ReaderFromTextScannerWithOptions(s *bufio.Scanner, opts EncoderOptions) *TextEncoder {
+	if opts.Dictionary == nil {
+		opts.Dictionary = DefaultDictionary
+	}
+	e := &TextEncoder{wordScanner: s, opts: opts, prosigns: make(map[string]Code, len(opts.Prosigns))}
+	// If names only differ in case, the first in sorted order (i.e. the uppercase one) is used
+	names := make([]string, 0, len(opts.Prosigns))
+	for name := range opts.Prosigns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if _, ok := e.prosigns[strings.ToUpper(name)]; !ok {
+			e.prosigns[strings.ToUpper(name)] = opts.Prosigns[name]
+		}
+	}
+	s.Split(e.scanWords) // todo what if the input has no spaces?
 	// todo also doesn't handle newlines
-	return &TextEncoder{wordScanner: s}
+	return e
+}
+
+// scanWords wraps bufio.ScanWords to keep track of the offset of each word
+func (e *TextEncoder) scanWords(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	advance, token, err = bufio.ScanWords(data, atEOF)
+	if token != nil {
+		// The token is a slice of data, so the difference
+		// in capacity is the offset of the token in data
+		e.wordOffset = e.scanned + cap(data) - cap(token)
+	}
+	e.scanned += advance
+	return
 }
 
 func (e *TextEncoder) Read(p []Signal) (n int, err error) {
@@ -33,6 +149,10 @@ func (e *TextEncoder) Read(p []Signal) (n int, err error) {
 	n = e.overflow.Empty(p)
 	p = p[n:]
 
+	if e.err != nil {
+		return n, e.err
+	}
+
 	// While there is space in p and there are words to encode
 	for len(p) > 0 {
 		if !e.wordScanner.Scan() {
@@ -56,16 +176,27 @@ func (e *TextEncoder) Read(p []Signal) (n int, err error) {
 			e.started = true
 		}
 
-		// Iterate over the runes of the word
-		for i, r := range word {
-			runeCode := Dictionary.FromRune(r)
-			if runeCode == nil {
-				runeCode = Dictionary.FromRune('?')
+		// Convert the word into the runes that are actually sent
+		shifts := e.opts.Dictionary.Shifts()
+		sent := []rune(e.decompose(word, shifts))
+
+		// Iterate over the runes of the word, matching the longest entry each time
+		letters := 0
+		for rs := sent; len(rs) > 0; {
+			codes, runesMatched := e.encodeRunes(rs, shifts)
+			if runesMatched == 0 {
+				r, offset := e.origin(word, shifts, len(sent)-len(rs))
+				e.err = &UnknownRuneError{Rune: r, Offset: e.wordOffset + offset}
+				return n, e.err
 			}
-			wordCode = append(wordCode, runeCode...)
-			if i+1 < len(word) {
-				wordCode = append(wordCode, RuneSpace)
+			for _, runeCode := range codes {
+				if letters > 0 {
+					wordCode = append(wordCode, RuneSpace)
+				}
+				wordCode = append(wordCode, runeCode...)
+				letters++
 			}
+			rs = rs[runesMatched:]
 		}
 
 		// Copy the code into p (with the remaining going into the buffer)
@@ -77,6 +208,170 @@ func (e *TextEncoder) Read(p []Signal) (n int, err error) {
 	return
 }
 
+// encodeRunes returns the code of the longest entry at the start of the given runes,
+// preceded by the code of any shifts needed to get to the entry's Dictionary.
+// If the first rune isn't in any of the dictionaries, it is handled by the
+// UnknownRunePolicy, after being transliterated (unless strict).
+// Also returns the number of runes that were encoded, which is 0 if the rune
+// is unknown and the policy is ErrorOnUnknown
+func (e *TextEncoder) encodeRunes(rs []rune, shifts []Shift) ([]Code, int) {
+	if c, n := e.prosign(rs); n > 0 {
+		// Prosigns are sent in the main dictionary, so shift back out first
+		if e.shift != nil {
+			out := e.shift.Out
+			e.shift = nil
+			return []Code{out, c}, n
+		}
+		return []Code{c}, n
+	}
+
+	if codes, n := e.lookup(rs, shifts); n > 0 {
+		return codes, n
+	}
+
+	if !e.opts.Strict {
+		if replacement, ok := e.transliterate(rs[0], shifts); ok {
+			e.substitute(rs[0], replacement)
+			return e.encodeReplacement(replacement, shifts), 1
+		}
+	}
+
+	if e.opts.UnknownRunes == ErrorOnUnknown {
+		return nil, 0
+	}
+
+	if e.opts.UnknownRunes == SkipUnknown {
+		e.substitute(rs[0], "")
+		return nil, 1
+	}
+	e.substitute(rs[0], "?")
+	if e.opts.Replacement != nil {
+		return []Code{e.opts.Replacement}, 1
+	}
+	return e.encodeReplacement("?", shifts), 1
+}
+
+// prosignMarkup maps the opening bracket of prosign markup to the closing bracket
+var prosignMarkup = map[rune]rune{'<': '>', '[': ']'}
+
+// prosign returns the code of the prosign markup at the start of the given
+// runes, and the number of runes in the markup (or 0 if there isn't any)
+func (e *TextEncoder) prosign(rs []rune) (Code, int) {
+	closing, ok := prosignMarkup[rs[0]]
+	if !ok {
+		return nil, 0
+	}
+	end := -1
+	for i, r := range rs[1:] {
+		if r == closing {
+			end = i + 1
+			break
+		}
+	}
+	if end < 2 {
+		return nil, 0
+	}
+	name := rs[1:end]
+
+	if c, ok := e.prosigns[strings.ToUpper(string(name))]; ok {
+		return c, end + 1
+	}
+
+	letters := make([]Code, 0, len(name))
+	for _, r := range name {
+		c := e.opts.Dictionary.FromRune(r)
+		if c == nil {
+			return nil, 0
+		}
+		letters = append(letters, c)
+	}
+	return JoinSignals(letters...), end + 1
+}
+
+// decompose converts the word into the runes that are actually sent,
+// with the Composer of each of the encoder's dictionaries
+func (e *TextEncoder) decompose(word string, shifts []Shift) string {
+	for _, d := range e.dictionaries(shifts) {
+		word = d.Decompose(word)
+	}
+	return word
+}
+
+// origin returns the rune of the word that the i-th rune of its decomposition
+// came from, and the offset of the rune in the word (in bytes). The Composers
+// work on whole words, so this decomposes longer and longer parts of the word
+// until the decomposition reaches the i-th rune
+func (e *TextEncoder) origin(word string, shifts []Shift, i int) (rune, int) {
+	for offset, r := range word {
+		if utf8.RuneCountInString(e.decompose(word[:offset+utf8.RuneLen(r)], shifts)) > i {
+			return r, offset
+		}
+	}
+	r, size := utf8.DecodeLastRuneInString(word)
+	return r, len(word) - size
+}
+
+// encodeReplacement returns the code of the text that replaces an unknown rune
+func (e *TextEncoder) encodeReplacement(replacement string, shifts []Shift) []Code {
+	codes := make([]Code, 0, len(replacement))
+	for rs := []rune(replacement); len(rs) > 0; {
+		replacementCodes, n := e.lookup(rs, shifts)
+		if n == 0 {
+			// Can only happen if '?' isn't in the dictionary
+			break
+		}
+		codes = append(codes, replacementCodes...)
+		rs = rs[n:]
+	}
+	return codes
+}
+
+// dictionaries returns the options' Dictionary followed by the shifted dictionaries
+func (e *TextEncoder) dictionaries(shifts []Shift) []*Dictionary {
+	dicts := make([]*Dictionary, 0, len(shifts)+1)
+	dicts = append(dicts, e.opts.Dictionary)
+	for _, shift := range shifts {
+		dicts = append(dicts, shift.Dictionary)
+	}
+	return dicts
+}
+
+// lookup returns the code of the longest entry at the start of the given runes,
+// preceded by the code of any shifts needed to get to the entry's Dictionary.
+// Also returns the number of runes that were encoded, or 0 if unknown
+func (e *TextEncoder) lookup(rs []rune, shifts []Shift) ([]Code, int) {
+	dict := e.opts.Dictionary
+	if e.shift != nil {
+		dict = e.shift.Dictionary
+	}
+	if c, n := dict.match(rs); n > 0 {
+		return []Code{c}, n
+	}
+
+	// Shift back out if the runes are in the main dictionary
+	if e.shift != nil {
+		if c, n := e.opts.Dictionary.match(rs); n > 0 {
+			out := e.shift.Out
+			e.shift = nil
+			return []Code{out, c}, n
+		}
+	}
+
+	// Otherwise try and shift into another dictionary
+	for i := range shifts {
+		if c, n := shifts[i].Dictionary.match(rs); n > 0 {
+			codes := make([]Code, 0, 3)
+			if e.shift != nil {
+				codes = append(codes, e.shift.Out)
+			}
+			e.shift = &shifts[i]
+			return append(codes, e.shift.In, c), n
+		}
+	}
+
+	return nil, 0
+}
+
 // FromText returns the Morse code of the given human-readable string
 func FromText(text string) Code {
 	e := ReaderFromText(strings.NewReader(text))
@@ -87,3 +382,21 @@ func FromText(text string) Code {
 	}
 	return c
 }
+
+// FromTextWithDictionary returns the Morse code of the given human-readable string,
+// using the given Dictionary
+func FromTextWithDictionary(text string, d *Dictionary) Code {
+	e := ReaderFromTextWithOptions(strings.NewReader(text), EncoderOptions{Dictionary: d})
+	c, err := ReadAll(e)
+	if err != nil {
+		// panic on error as neither strings.Reader or TextEncoder should ever error
+		panic(err)
+	}
+	return c
+}
+
+// FromTextWithOptions returns the Morse code of the given human-readable string,
+// configured by opts. Only returns an error if opts.UnknownRunes is ErrorOnUnknown
+func FromTextWithOptions(text string, opts EncoderOptions) (Code, error) {
+	return ReadAll(ReaderFromTextWithOptions(strings.NewReader(text), opts))
+}
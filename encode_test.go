@@ -40,6 +40,135 @@ func TestFromText(t *testing.T) {
 	a.Equal(io.EOF, err)
 }
 
+func TestReaderFromTextWithOptions(t *testing.T) {
+	a := assert.New(t)
+
+	d := NewDictionary()
+	d.Add('a', B)
+	d.Add('?', QuestionMark)
+
+	c, err := ReadAll(ReaderFromTextWithOptions(strings.NewReader("ab"), EncoderOptions{Dictionary: d}))
+	a.NoError(err)
+	a.Equal(JoinLetters(B, QuestionMark).String(), Code(c).String())
+
+	// The default dictionary should be unaffected
+	a.Equal(JoinLetters(A, B).String(), FromText("ab").String())
+}
+
+func TestTextEncoder_UnknownRunes(t *testing.T) {
+	a := assert.New(t)
+
+	text := "hëllo  wor☃d"
+
+	c, err := FromTextWithOptions(text, EncoderOptions{})
+	a.NoError(err)
+	a.Equal(FromText("hello wor?d").String(), c.String())
+
+	c, err = FromTextWithOptions(text, EncoderOptions{Replacement: X})
+	a.NoError(err)
+	a.Equal(FromText("hello worxd").String(), c.String())
+
+	c, err = FromTextWithOptions(text, EncoderOptions{UnknownRunes: SkipUnknown})
+	a.NoError(err)
+	a.Equal(FromText("hello word").String(), c.String())
+
+	// Runes are transliterated before they're reported, like the other policies
+	c, err = FromTextWithOptions("Zoë", EncoderOptions{UnknownRunes: ErrorOnUnknown})
+	a.NoError(err)
+	a.Equal(FromText("zoe").String(), c.String())
+	_, err = FromTextWithOptions("Zoë", EncoderOptions{Strict: true, UnknownRunes: ErrorOnUnknown})
+	a.Equal(&UnknownRuneError{Rune: 'ë', Offset: 2}, err)
+
+	r := ReaderFromTextWithOptions(strings.NewReader("hello  wor☃d"), EncoderOptions{UnknownRunes: ErrorOnUnknown})
+	c, err = ReadAll(r)
+	a.Equal(&UnknownRuneError{Rune: '☃', Offset: 10}, err)
+	a.EqualError(err, "morse: unknown rune '☃' at offset 10")
+	// The words before the unknown rune should still be read
+	a.Equal(FromText("hello").String(), c.String())
+
+	// The error should persist
+	n, err := r.Read(make([]Signal, 1))
+	a.Equal(0, n)
+	a.Equal(&UnknownRuneError{Rune: '☃', Offset: 10}, err)
+
+	// The offset is of the rune as it was written, not as it was decomposed
+	_, err = FromTextWithOptions("한 국☃", EncoderOptions{Dictionary: Korean, UnknownRunes: ErrorOnUnknown})
+	a.Equal(&UnknownRuneError{Rune: '☃', Offset: 7}, err)
+	_, err = FromTextWithOptions("ガ☃", EncoderOptions{Dictionary: Wabun, UnknownRunes: ErrorOnUnknown})
+	a.Equal(&UnknownRuneError{Rune: '☃', Offset: 3}, err)
+}
+
+func TestTextEncoder_Prosigns(t *testing.T) {
+	a := assert.New(t)
+
+	a.Equal(JoinWords(JoinLetters(T, E, S, T), JoinSignals(A, R)).String(), FromText("test <AR>").String())
+	a.Equal(JoinLetters(E, JoinSignals(S, K), E).String(), FromText("e[sk]e").String())
+	a.Equal(JoinSignals(C, T).String(), FromText("<CT>").String())
+
+	// Invalid markup should be encoded as-is
+	a.Equal(JoinLetters(QuestionMark, A, R).String(), FromText("<AR").String())
+	a.Equal(JoinLetters(QuestionMark, QuestionMark).String(), FromText("<>").String())
+	a.Equal(JoinLetters(QuestionMark, QuestionMark, QuestionMark).String(), FromText("<☃>").String())
+
+	c, err := FromTextWithOptions("<err> [SOS]", EncoderOptions{Prosigns: map[string]Code{
+		"ERR": FromCodeString("・・・・・・・・"),
+	}})
+	a.NoError(err)
+	a.Equal(JoinWords(FromCodeString("・・・・・・・・"), JoinSignals(S, O, S)).String(), c.String())
+
+	// Names are case-insensitive, and the uppercase name wins if they only differ in case
+	for i := 0; i < 10; i++ {
+		c, err = FromTextWithOptions("<Err>", EncoderOptions{Prosigns: map[string]Code{
+			"err": E, "ERR": T, "Err": A,
+		}})
+		a.NoError(err)
+		a.Equal(T.String(), c.String())
+	}
+
+	// Prosigns are sent after shifting back out of Wabun
+	c = FromText("イ<AR>")
+	a.Equal(JoinLetters(WabunIn, FromCodeString("・－"), WabunOut, JoinSignals(A, R)).String(), c.String())
+	b, err := io.ReadAll(NewDecoderWithOptions(NewReader(c), DecoderOptions{
+		Prosigns: map[string]Code{"AR": JoinSignals(A, R)},
+	}))
+	a.NoError(err)
+	a.Equal("イ<AR>", string(b))
+}
+
+func TestTextEncoder_Punctuation(t *testing.T) {
+	a := assert.New(t)
+
+	a.Equal(JoinLetters(Apostrophe, QuotationMark, Colon, Equals, Plus).String(), FromText(`'":=+`).String())
+	a.Equal(JoinLetters(ExclamationMark, Ampersand, Semicolon, Dollar, Underscore).String(), FromText("!&;$_").String())
+	a.Equal(JoinLetters(Two, X, Three).String(), FromText("2×3").String())
+	a.Equal(`'":=+!&;$_ É`, Decode(FromText(`'":=+!&;$_ é`)))
+}
+
+func TestTextEncoder_Strict(t *testing.T) {
+	a := assert.New(t)
+
+	opts := EncoderOptions{Dictionary: ITUDictionary, Strict: true, UnknownRunes: ErrorOnUnknown}
+	c, err := FromTextWithOptions(`Café: "2+2=4" @ 12:00 (ok)`, opts)
+	a.NoError(err)
+	a.Equal(`CAFÉ: "2+2=4" @ 12:00 (OK)`, Decode(c))
+
+	for text, r := range map[string]rune{"hi!": '!', "a & b": '&', "naïve": 'ï', "“quoted”": '“'} {
+		_, err = FromTextWithOptions(text, opts)
+		var unknownErr *UnknownRuneError
+		if a.ErrorAs(err, &unknownErr, text) {
+			a.Equal(r, unknownErr.Rune, text)
+		}
+	}
+
+	// Without Strict, runes are still transliterated into the ITU characters
+	c, err = FromTextWithOptions("naïve “quoted” ☃", EncoderOptions{Dictionary: ITUDictionary})
+	a.NoError(err)
+	a.Equal(`NAIVE "QUOTED" ?`, Decode(c))
+	c, err = FromTextWithOptions("naïve ☃", EncoderOptions{Dictionary: ITUDictionary, Strict: true})
+	a.NoError(err)
+	a.Equal(`NA?VE ?`, Decode(c))
+}
+
 const benchmarkTextEncoderSeed = 42
 const benchmarkTextEncoderBufferSize = 512
 
@@ -0,0 +1,94 @@
+package morse
+
+// The non-English Latin letters, from the ITU appendix and national variants.
+// Many are sent the same as each other (e.g. Ä, Ą and Æ), so they are only
+// encoded by DefaultDictionary, and decoded by the alphabet of the language
+var extendedLetters = map[rune]string{
+	'à': "・－－・－",
+	'á': "・－－・－",
+	'å': "・－－・－",
+	'ä': "・－・－",
+	'ą': "・－・－",
+	'æ': "・－・－",
+	'ć': "－・－・・",
+	'ĉ': "－・－・・",
+	'ç': "－・－・・",
+	'ð': "・・－－・",
+	'ę': "・・－・・",
+	'è': "・－・・－",
+	'ł': "・－・・－",
+	'ĝ': "－－・－・",
+	'ĥ': "－－－－",
+	'ĵ': "・－－－・",
+	'ñ': "－－・－－",
+	'ń': "－－・－－",
+	'ó': "－－－・",
+	'ö': "－－－・",
+	'ø': "－－－・",
+	'ś': "・・・－・・・",
+	'ŝ': "・・・－・",
+	'ß': "・・・－－・・",
+	'þ': "・－－・・",
+	'ü': "・・－－",
+	'ŭ': "・・－－",
+	'ź': "－－・・－",
+	'ż': "－－・・－・",
+}
+
+// CH is the German (and historically Spanish) digraph CH
+var CH = FromCodeString("－－－－")
+
+var (
+	// German is the Dictionary for German Morse code
+	German = newLatinDictionary("äöüß")
+
+	// Spanish is the Dictionary for Spanish Morse code
+	Spanish = newLatinDictionary("áñóü")
+
+	// French is the Dictionary for French Morse code
+	French = newLatinDictionary("àçè")
+
+	// Polish is the Dictionary for Polish Morse code
+	Polish = newLatinDictionary("ąćęłńóśźż")
+
+	// Esperanto is the Dictionary for Esperanto Morse code
+	Esperanto = newLatinDictionary("ĉĝĥĵŝŭ")
+
+	// Swedish is the Dictionary for Swedish and Finnish Morse code
+	Swedish = newLatinDictionary("åäö")
+
+	// Danish is the Dictionary for Danish and Norwegian Morse code
+	Danish = newLatinDictionary("æøå")
+
+	// Icelandic is the Dictionary for Icelandic Morse code
+	Icelandic = newLatinDictionary("ðþæö")
+)
+
+// newLatinDictionary creates a Dictionary with the English letters,
+// digits and punctuation, as well as the given extended letters
+func newLatinDictionary(extended string) *Dictionary {
+	d := NewDictionary()
+	addLetters(d)
+	d.Add(' ', Space)
+	addDigits(d)
+	addPunctuation(d)
+	addExtendedPunctuation(d)
+	for _, r := range extended {
+		d.AddCodeString(r, extendedLetters[r])
+	}
+	return d
+}
+
+// addExtendedLetters adds all the extended letters to the dictionary, only for encoding
+func addExtendedLetters(d *Dictionary) {
+	for r, codeStr := range extendedLetters {
+		d.addText(string(r), FromCodeString(codeStr), false)
+	}
+}
+
+func init() {
+	German.AddText("ch", CH)
+
+	Spanish.AddCodeString('¿', "・・－・－")
+	Spanish.AddCodeString('¡', "－－・・・－")
+}
@@ -0,0 +1,30 @@
+package morse
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestExtendedLetters(t *testing.T) {
+	a := assert.New(t)
+
+	// The default dictionary encodes the extended letters, but doesn't decode them
+	code := FromText("Ä é Ñ")
+	a.Equal(JoinWords(FromCodeString("・－・－"), FromCodeString("・・－・・"), FromCodeString("－－・－－")).String(),
+		code.String())
+	a.Equal("? É ?", Decode(code))
+
+	// English text is unaffected by the German CH
+	a.Equal(JoinLetters(C, H).String(), FromText("ch").String())
+
+	a.Equal("ÄÖÜ", DecodeWithDictionary(FromTextWithDictionary("äöü", German), German))
+	a.Equal("AÑO", DecodeWithDictionary(FromTextWithDictionary("año", Spanish), Spanish))
+	a.Equal("ŁÓDŹ", DecodeWithDictionary(FromTextWithDictionary("Łódź", Polish), Polish))
+	a.Equal("ĈU", DecodeWithDictionary(FromTextWithDictionary("ĉu", Esperanto), Esperanto))
+
+	// The longest entry should be matched first
+	code = FromTextWithDictionary("Buch", German)
+	a.Equal(JoinLetters(B, U, CH).String(), code.String())
+	a.Equal("BUCH", DecodeWithDictionary(code, German))
+	a.Equal(JoinLetters(C, A).String(), FromTextWithDictionary("ca", German).String())
+}
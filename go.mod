@@ -4,6 +4,7 @@ go 1.18
 
 require (
 	github.com/stretchr/testify v1.7.1
+	golang.org/x/text v0.14.0
 	gopkg.in/loremipsum.v1 v1.1.0
 )
 
@@ -0,0 +1,195 @@
+package morse
+
+import "strings"
+
+// Korean is the Dictionary for SKATS (Standard Korean Alphabet Transliteration
+// System), the Korean variant of Morse. Only the basic jamo have codes, so
+// Hangul syllables are decomposed into jamo when encoding, and jamo are
+// recomposed into syllables when decoding
+var Korean = NewDictionary()
+
+// The first Hangul syllable (가) and the number of syllables,
+// which are arranged by lead, then vowel, then tail jamo
+const (
+	hangulSyllableBase  = 0xAC00
+	hangulSyllableCount = 11172
+	hangulVowelCount    = 21
+	hangulTailCount     = 28
+)
+
+// The (compatibility) jamo of each position in a Hangul syllable, in Unicode order
+var (
+	hangulLeads  = []rune("ㄱㄲㄴㄷㄸㄹㅁㅂㅃㅅㅆㅇㅈㅉㅊㅋㅌㅍㅎ")
+	hangulVowels = []rune("ㅏㅐㅑㅒㅓㅔㅕㅖㅗㅘㅙㅚㅛㅜㅝㅞㅟㅠㅡㅢㅣ")
+	// The first tail is 0, as a syllable doesn't need a tail
+	hangulTails = []rune("\x00ㄱㄲㄳㄴㄵㄶㄷㄹㄺㄻㄼㄽㄾㄿㅀㅁㅂㅄㅅㅆㅇㅈㅊㅋㅌㅍㅎ")
+)
+
+// The jamo that are sent as two basic jamo, e.g. ㄲ is sent as ㄱㄱ
+var hangulCompoundJamo = map[rune][2]rune{
+	'ㄲ': {'ㄱ', 'ㄱ'}, 'ㄸ': {'ㄷ', 'ㄷ'}, 'ㅃ': {'ㅂ', 'ㅂ'}, 'ㅆ': {'ㅅ', 'ㅅ'}, 'ㅉ': {'ㅈ', 'ㅈ'},
+	'ㄳ': {'ㄱ', 'ㅅ'}, 'ㄵ': {'ㄴ', 'ㅈ'}, 'ㄶ': {'ㄴ', 'ㅎ'}, 'ㄺ': {'ㄹ', 'ㄱ'}, 'ㄻ': {'ㄹ', 'ㅁ'},
+	'ㄼ': {'ㄹ', 'ㅂ'}, 'ㄽ': {'ㄹ', 'ㅅ'}, 'ㄾ': {'ㄹ', 'ㅌ'}, 'ㄿ': {'ㄹ', 'ㅍ'}, 'ㅀ': {'ㄹ', 'ㅎ'},
+	'ㅄ': {'ㅂ', 'ㅅ'},
+	'ㅘ': {'ㅗ', 'ㅏ'}, 'ㅙ': {'ㅗ', 'ㅐ'}, 'ㅚ': {'ㅗ', 'ㅣ'}, 'ㅝ': {'ㅜ', 'ㅓ'}, 'ㅞ': {'ㅜ', 'ㅔ'},
+	'ㅟ': {'ㅜ', 'ㅣ'}, 'ㅢ': {'ㅡ', 'ㅣ'}, 'ㅒ': {'ㅑ', 'ㅣ'}, 'ㅖ': {'ㅕ', 'ㅣ'},
+}
+
+// hangulComposer is the Composer for Korean, which
+// splits and joins Hangul syllables and their jamo
+type hangulComposer struct {
+	// The reverse of hangulCompoundJamo
+	compound map[[2]rune]rune
+	// The index of each jamo in hangulLeads, hangulVowels and hangulTails
+	leads, vowels, tails map[rune]int
+}
+
+func newHangulComposer() *hangulComposer {
+	c := &hangulComposer{
+		compound: make(map[[2]rune]rune, len(hangulCompoundJamo)),
+		leads:    make(map[rune]int, len(hangulLeads)),
+		vowels:   make(map[rune]int, len(hangulVowels)),
+		tails:    make(map[rune]int, len(hangulTails)),
+	}
+	for r, basic := range hangulCompoundJamo {
+		c.compound[basic] = r
+	}
+	for i, r := range hangulLeads {
+		c.leads[r] = i
+	}
+	for i, r := range hangulVowels {
+		c.vowels[r] = i
+	}
+	// Skip the empty tail
+	for i, r := range hangulTails[1:] {
+		c.tails[r] = i + 1
+	}
+	return c
+}
+
+// writeBasicJamo writes the basic jamo that r is sent as
+func writeBasicJamo(sb *strings.Builder, r rune) {
+	if basic, ok := hangulCompoundJamo[r]; ok {
+		sb.WriteRune(basic[0])
+		sb.WriteRune(basic[1])
+	} else {
+		sb.WriteRune(r)
+	}
+}
+
+func (c *hangulComposer) Decompose(s string) string {
+	sb := strings.Builder{}
+	for _, r := range s {
+		if r < hangulSyllableBase || r >= hangulSyllableBase+hangulSyllableCount {
+			writeBasicJamo(&sb, r)
+			continue
+		}
+		i := int(r - hangulSyllableBase)
+		writeBasicJamo(&sb, hangulLeads[i/(hangulVowelCount*hangulTailCount)])
+		writeBasicJamo(&sb, hangulVowels[(i/hangulTailCount)%hangulVowelCount])
+		if tail := i % hangulTailCount; tail != 0 {
+			writeBasicJamo(&sb, hangulTails[tail])
+		}
+	}
+	return sb.String()
+}
+
+func (c *hangulComposer) Compose(s string) string {
+	rs := []rune(s)
+	isVowel := func(i int) bool {
+		if i >= len(rs) {
+			return false
+		}
+		_, ok := c.vowels[rs[i]]
+		return ok
+	}
+
+	sb := strings.Builder{}
+	for i := 0; i < len(rs); {
+		// Find the lead, which may be doubled (e.g. ㄱㄱ is ㄲ)
+		lead, leadOk := c.leads[rs[i]]
+		leadLen := 1
+		if i+2 < len(rs) && isVowel(i+2) {
+			if double, ok := c.compound[[2]rune{rs[i], rs[i+1]}]; ok {
+				if l, ok := c.leads[double]; ok {
+					lead, leadOk = l, true
+					leadLen = 2
+				}
+			}
+		}
+		if !leadOk || !isVowel(i+leadLen) {
+			// Not the start of a syllable, so leave the rune as-is
+			sb.WriteRune(rs[i])
+			i++
+			continue
+		}
+		i += leadLen
+
+		// Find the vowel, which may be a compound (e.g. ㅗㅏ is ㅘ)
+		vowel := c.vowels[rs[i]]
+		i++
+		if i < len(rs) {
+			if compound, ok := c.compound[[2]rune{rs[i-1], rs[i]}]; ok {
+				if v, ok := c.vowels[compound]; ok {
+					vowel = v
+					i++
+				}
+			}
+		}
+
+		// Find the tail. A consonant followed by a vowel is
+		// the lead of the next syllable, rather than a tail
+		tail := 0
+		if i < len(rs) && !isVowel(i+1) {
+			if t, ok := c.tails[rs[i]]; ok {
+				tail = t
+				i++
+				// The tail may be a compound (e.g. ㄹㄱ is ㄺ)
+				if i < len(rs) && !isVowel(i+1) {
+					if compound, ok := c.compound[[2]rune{rs[i-1], rs[i]}]; ok {
+						if t, ok := c.tails[compound]; ok {
+							tail = t
+							i++
+						}
+					}
+				}
+			}
+		}
+
+		sb.WriteRune(rune(hangulSyllableBase + (lead*hangulVowelCount+vowel)*hangulTailCount + tail))
+	}
+	return sb.String()
+}
+
+func init() {
+	Korean.AddCodeString('ㄱ', "・－・・")
+	Korean.AddCodeString('ㄴ', "・・－・")
+	Korean.AddCodeString('ㄷ', "－・・・")
+	Korean.AddCodeString('ㄹ', "・・・－")
+	Korean.AddCodeString('ㅁ', "－－")
+	Korean.AddCodeString('ㅂ', "・－－")
+	Korean.AddCodeString('ㅅ', "－－・")
+	Korean.AddCodeString('ㅇ', "－・－")
+	Korean.AddCodeString('ㅈ', "・－－・")
+	Korean.AddCodeString('ㅊ', "－・－・")
+	Korean.AddCodeString('ㅋ', "－・・－")
+	Korean.AddCodeString('ㅌ', "－－・・")
+	Korean.AddCodeString('ㅍ', "－－－")
+	Korean.AddCodeString('ㅎ', "・－－－")
+	Korean.AddCodeString('ㅏ', "・")
+	Korean.AddCodeString('ㅑ', "・・")
+	Korean.AddCodeString('ㅓ', "－")
+	Korean.AddCodeString('ㅕ', "・・・")
+	Korean.AddCodeString('ㅗ', "・－")
+	Korean.AddCodeString('ㅛ', "－・")
+	Korean.AddCodeString('ㅜ', "・・・・")
+	Korean.AddCodeString('ㅠ', "・－・")
+	Korean.AddCodeString('ㅡ', "－・・")
+	Korean.AddCodeString('ㅣ', "・・－")
+	Korean.AddCodeString('ㅐ', "－－・－")
+	Korean.AddCodeString('ㅔ', "－・－－")
+	addDigits(Korean)
+	addPunctuation(Korean)
+
+	Korean.SetComposer(newHangulComposer())
+}
@@ -0,0 +1,26 @@
+package morse
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestKorean(t *testing.T) {
+	a := assert.New(t)
+
+	// 한 is sent as ㅎㅏㄴ
+	code := FromTextWithDictionary("한", Korean)
+	a.Equal(JoinLetters(J, E, F).String(), code.String())
+	a.Equal("한", DecodeWithDictionary(code, Korean))
+
+	for _, text := range []string{
+		"안녕하세요", "대한민국", "감사합니다", "읽다", "값", "의사", "꿈", "뛰어", "과일", "왜", "삶 123",
+	} {
+		a.Equal(text, DecodeWithDictionary(FromTextWithDictionary(text, Korean), Korean))
+	}
+
+	// Compound jamo are sent as their basic jamo
+	a.Equal(JoinLetters(L, L, E).String(), FromTextWithDictionary("까", Korean).String())
+	a.Equal(JoinLetters(A, E).String(), FromTextWithDictionary("ㅘ", Korean).String())
+	a.Equal("ㄱ", DecodeWithDictionary(L, Korean))
+}
@@ -3,6 +3,8 @@
 package morse
 
 import (
+	"errors"
+	"fmt"
 	"strings"
 	"time"
 )
@@ -61,13 +63,11 @@ func (c Code) Equal(o Code) bool {
 	return true
 }
 
-// DitDuration returns the duration of the code, relative to a Dit.
+// DitDuration returns the duration of the code, relative to a Dit. Like
+// Duration, American Morse timing is used if the code has American signals.
 // See Signal.DitDuration for more info
-func (c Code) DitDuration() (d uint) {
-	for _, s := range c {
-		d += s.DitDuration()
-	}
-	return
+func (c Code) DitDuration() uint {
+	return c.DitDurationWithTiming(c.timing())
 }
 
 // Duration returns the duration of the code, at the given WPM.
@@ -76,12 +76,20 @@ func (c Code) DitDuration() (d uint) {
 // If farnsworthWPM is non-zero, the duration uses Farnsworth timing,
 // where the speed of the characters is determined by wpm, but the
 // actual words per minute is determined by farnsworthWPM. This is
-// achieved by elongating the duration between letters and words
+// achieved by elongating the duration between letters and words.
+//
+// If the code has any signals only used by American Morse (LongDah, ExtraLongDah
+// or InternalSpace), AmericanTiming is used. Otherwise International Morse timing
+// is used, so American Morse without those signals (e.g. "SEE") must use
+// DurationWithTiming with AmericanTiming to be timed correctly
 func (c Code) Duration(wpm, farnsworthWPM uint) (d time.Duration) {
-	for _, s := range c {
-		d += s.Duration(wpm, farnsworthWPM)
-	}
-	return d
+	return c.DurationWithTiming(wpm, farnsworthWPM, c.timing())
+}
+
+// key returns the signals of the code as a string, for use as a map key.
+// Unlike String, every code has a unique key
+func (c Code) key() string {
+	return string(signalArrayToByteArray(c))
 }
 
 func (c Code) String() string {
@@ -91,3 +99,107 @@ func (c Code) String() string {
 	}
 	return sb.String()
 }
+
+// Words splits the code into its words, i.e. the codes between signals at least
+// as long as a WordSpace, similar to strings.Fields (so there are no empty words).
+// The words share the underlying array of the code
+func (c Code) Words() []Code {
+	return c.split(WordSpace)
+}
+
+// Runes splits the code into its characters, i.e. the codes between signals at
+// least as long as a RuneSpace (including WordSpaces), similar to strings.Fields
+// (so there are no empty characters). The characters share the underlying array
+// of the code
+func (c Code) Runes() []Code {
+	return c.split(RuneSpace)
+}
+
+// split splits the code at inaudible signals at least as long as the delimiter
+func (c Code) split(delimiter Signal) (codes []Code) {
+	start := 0
+	for i, s := range c {
+		if !s.Audible() && s.DitDuration() >= delimiter.DitDuration() {
+			if i > start {
+				codes = append(codes, c[start:i])
+			}
+			start = i + 1
+		}
+	}
+	if len(c) > start {
+		codes = append(codes, c[start:])
+	}
+	return
+}
+
+// Errors wrapped by the *CodeError returned by Code.Validate
+var (
+	ErrAdjacentAudible = errors.New("audible signals without a space between them")
+	ErrAdjacentSpaces  = errors.New("spaces without an audible signal between them")
+	ErrLeadingSpace    = errors.New("leading space")
+	ErrTrailingSpace   = errors.New("trailing space")
+)
+
+// CodeError is returned by Code.Validate when the code isn't well-formed
+type CodeError struct {
+	// Offset is the index of the signal the error is at
+	Offset int
+	Err    error
+}
+
+func (e *CodeError) Error() string {
+	return fmt.Sprintf("morse: invalid code at signal %d: %v", e.Offset, e.Err)
+}
+
+func (e *CodeError) Unwrap() error {
+	return e.Err
+}
+
+// Validate returns a *CodeError if the code isn't well-formed, i.e. if
+// it doesn't alternate between audible signals and spaces, or starts or
+// ends with a space. An empty code is valid. See Normalize
+func (c Code) Validate() error {
+	for i, s := range c {
+		switch {
+		case i == 0 && !s.Audible():
+			return &CodeError{Offset: i, Err: ErrLeadingSpace}
+		case i > 0 && s.Audible() && c[i-1].Audible():
+			return &CodeError{Offset: i, Err: ErrAdjacentAudible}
+		case i > 0 && !s.Audible() && !c[i-1].Audible():
+			return &CodeError{Offset: i, Err: ErrAdjacentSpaces}
+		case i == len(c)-1 && !s.Audible():
+			return &CodeError{Offset: i, Err: ErrTrailingSpace}
+		}
+	}
+	return nil
+}
+
+// Normalize returns a well-formed copy of the code (see Validate), where
+// consecutive spaces are merged into the longest of them, a SignalSpace is
+// put between consecutive audible signals, and spaces at the start and end
+// are trimmed. For example,
+//
+//	Code{RuneSpace, Dit, Dah, RuneSpace, WordSpace, Dit, RuneSpace}
+//
+// is normalized to
+//
+//	Code{Dit, SignalSpace, Dah, WordSpace, Dit}
+func (c Code) Normalize() Code {
+	code := make(Code, 0, len(c))
+	// The longest space since the last audible signal
+	space, spaced := SignalSpace, false
+	for _, s := range c {
+		if !s.Audible() {
+			if !spaced || s.DitDuration() > space.DitDuration() {
+				space, spaced = s, true
+			}
+			continue
+		}
+		if len(code) > 0 {
+			code = append(code, space)
+		}
+		code = append(code, s)
+		space, spaced = SignalSpace, false
+	}
+	return code
+}
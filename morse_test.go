@@ -0,0 +1,66 @@
+package morse
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCode_Words(t *testing.T) {
+	a := assert.New(t)
+
+	sos := JoinLetters(S, O, S)
+	a.Equal([]Code{sos, sos}, JoinWords(sos, sos).Words())
+	a.Nil(Code{WordSpace, WordSpace}.Words())
+	a.Equal([]Code{sos, sos}, append(append(Code{WordSpace}, JoinWords(sos, Code{}, sos)...), WordSpace).Words())
+	a.Nil(Code{}.Words())
+}
+
+func TestCode_Runes(t *testing.T) {
+	a := assert.New(t)
+
+	a.Equal([]Code{S, O, S, E}, JoinWords(JoinLetters(S, O, S), E).Runes())
+	a.Equal([]Code{JoinSignals(S, O, S)}, JoinSignals(S, O, S).Runes())
+	a.Nil(Code{RuneSpace}.Runes())
+}
+
+func TestCode_Validate(t *testing.T) {
+	a := assert.New(t)
+
+	a.NoError(Code{}.Validate())
+	a.NoError(JoinWords(JoinLetters(S, O, S), E).Validate())
+	a.NoError(americanCode(".. .").Validate())
+
+	tests := []struct {
+		code   Code
+		offset int
+		err    error
+	}{
+		{Code{Dit, Dah}, 1, ErrAdjacentAudible},
+		{Code{RuneSpace, Dit}, 0, ErrLeadingSpace},
+		{Code{Dit, SignalSpace}, 1, ErrTrailingSpace},
+		{Code{Dit, RuneSpace, WordSpace, Dah}, 2, ErrAdjacentSpaces},
+	}
+	for _, test := range tests {
+		err := test.code.Validate()
+		var codeErr *CodeError
+		if a.True(errors.As(err, &codeErr), test.code) {
+			a.Equal(test.offset, codeErr.Offset)
+			a.ErrorIs(err, test.err)
+		}
+	}
+}
+
+func TestCode_Normalize(t *testing.T) {
+	a := assert.New(t)
+
+	a.Equal(Code{Dit, SignalSpace, Dah, WordSpace, Dit},
+		Code{RuneSpace, Dit, Dah, RuneSpace, WordSpace, Dit, RuneSpace}.Normalize())
+	a.Equal(Code{}, Code{WordSpace, SignalSpace}.Normalize())
+
+	valid := JoinWords(JoinLetters(S, O, S), E)
+	a.Equal(valid, valid.Normalize())
+	for _, code := range []Code{{Dit, Dah}, {SignalSpace, RuneSpace, Dit, Dit, SignalSpace}} {
+		a.NoError(code.Normalize().Validate())
+	}
+}
@@ -1,5 +1,5 @@
 // Package prosign contains definitions for common Morse prosigns.
-// Importing the package automatically adds some prosigns to morse.Dictionary
+// Importing the package automatically adds some prosigns to morse.DefaultDictionary
 package prosign
 
 import "github.com/bhollier/morse"
@@ -62,7 +62,7 @@ var (
 	AllBefore = morse.JoinLetters(morse.A, morse.B)
 
 	// AllBetween The portion of the message to which I refer is all that falls between ... and ... (Morse abbr.)
-	AllBetween = morse.JoinLetters(morse.A, morse.B)
+	AllBetween = morse.JoinLetters(morse.B, morse.N)
 
 	// SayAgain When standing alone, a note of interrogation or request for repetition of a transmission not understood.
 	// When ? is placed after a coded signal, modifies the code to be a question or request.
@@ -102,6 +102,9 @@ var (
 	// Attention Message begins / Start of work / New message
 	Attention = morse.JoinSignals(morse.K, morse.A)
 
+	// EndOfContact End of contact / End of work. Sent at the end of the final transmission of a contact.
+	EndOfContact = morse.JoinSignals(morse.S, morse.K)
+
 	// Acknowledge Message received (Morse abbr.).
 	Acknowledge = morse.JoinLetters(morse.C, morse.F, morse.M)
 
@@ -117,13 +120,6 @@ var (
 
 // todo add more
 
-var Prosigns = []morse.Code{
-	ThisIsFrom, UnknownStation, NothingHeard, Rodger, Over, Closing, Calling, CallingFor, Who, Wait, WaitOut, Verified,
-	WordAfter, WordBefore, AllAfter, AllBefore, AllAfter, AllBetween, SayAgain, Interrogative, Correction, Correct,
-	Negative, Wrong, DisregardThisTransmission, TimeIs, RequestTimeCheck, Break, BreakIn, Attention, Acknowledge,
-	WeatherIs, Newline,
-}
-
 func init() {
-	morse.Dictionary.Add('\n', Newline)
+	morse.DefaultDictionary.Add('\n', Newline)
 }
@@ -0,0 +1,140 @@
+package prosign
+
+import (
+	"github.com/bhollier/morse"
+	"strings"
+)
+
+// Prosign describes a prosign (or Morse abbreviation)
+type Prosign struct {
+	// Name is the name of the prosign, e.g. "Out"
+	Name string
+	// Notation is the letters the prosign is written as, e.g. "AR"
+	Notation string
+	// Code is the Morse code of the prosign
+	Code morse.Code
+	// Description is the meaning of the prosign
+	Description string
+	// RunTogether is whether the letters of the prosign are sent without
+	// a space between them (see morse.JoinSignals), rather than as separate letters
+	RunTogether bool
+}
+
+// String returns the notation of the prosign
+func (p Prosign) String() string {
+	return p.Notation
+}
+
+// registry contains all the prosigns defined in the package. Where prosigns have
+// the same notation or code, the run-together prosign with the most common
+// meaning is first
+var registry = []Prosign{
+	{"Newline", "AA", Newline, "New line.", true},
+	{"UnknownStation", "AA", UnknownStation, "Used for directional signaling lights, but not in radiotelegraphy.", true},
+	{"Out", "AR", Out, "End of transmission / End of message / End of telegram.", true},
+	{"Wait", "AS", Wait, "I must pause for a few minutes.", true},
+	{"Break", "BT", Break, "Start new section of message.", true},
+	{"Correction", "HH", Correction, "Preceding text was in error. The following is the corrected text.", true},
+	{"Interrogative", "INT", Interrogative, "When placed before a signal, modifies the signal to be a question/request.", true},
+	{"Attention", "KA", Attention, "Message begins / Start of work / New message.", true},
+	{"EndOfContact", "SK", EndOfContact, "End of contact / End of work.", true},
+	{"SayAgain", "UD", SayAgain, "Request for repetition of a transmission not understood.", true},
+	{"Verified", "VE", Verified, "Message is verified.", true},
+	{"ThisIsFrom", "DE", ThisIsFrom, "Precedes the name or other identification of the calling station.", false},
+	{"NothingHeard", "NIL", NothingHeard, "The answer is \"nothing\" or \"none\" or \"not available\".", false},
+	{"Rodger", "R", Rodger, "The last transmission has been received.", false},
+	{"Over", "K", Over, "Invitation to transmit after terminating the call signal.", false},
+	{"Closing", "CL", Closing, "Announcing station shutdown.", false},
+	{"Calling", "CQ", Calling, "General call to any station.", false},
+	{"CallingFor", "CP", CallingFor, "General call to two or more specified stations.", false},
+	{"Who", "CS", Who, "What is the name or identity signal of your station?", false},
+	{"WaitOut", "AS AR", WaitOut, "I must pause for more than a few minutes.", false},
+	{"WordAfter", "WA", WordAfter, "The word after ...", false},
+	{"WordBefore", "WB", WordBefore, "The word before ...", false},
+	{"AllAfter", "AA", AllAfter, "The portion of the message to which I refer is all that follows the text ...", false},
+	{"AllBefore", "AB", AllBefore, "The portion of the message to which I refer is all that precedes the text ...", false},
+	{"AllBetween", "BN", AllBetween, "The portion of the message to which I refer is all that falls between ... and ...", false},
+	{"Correct", "C", Correct, "Answer to prior question is \"yes\".", false},
+	{"Negative", "N", Negative, "Answer to prior question is \"no\".", false},
+	{"Wrong", "ZWF", Wrong, "Your last transmission was wrong. The correct version is ...", false},
+	{"DisregardThisTransmission", "HH AR", DisregardThisTransmission, "The entire message just sent is in error, disregard it.", false},
+	{"TimeIs", "QTR", TimeIs, "The following is the correct UTC in HHMM 24-hour format.", false},
+	{"RequestTimeCheck", "QTR?", RequestTimeCheck, "What is the correct time?", false},
+	{"BreakIn", "BK", BreakIn, "Signal used to interrupt a transmission already in progress.", false},
+	{"Acknowledge", "CFM", Acknowledge, "Message received.", false},
+	{"WeatherIs", "WX", WeatherIs, "Weather report follows.", false},
+}
+
+// Prosigns contains the code of every prosign in the package.
+//
+// Deprecated: Use All, which also has the name and meaning of each prosign
+var Prosigns = make([]morse.Code, 0, len(registry))
+
+// Names maps the notation of the run-together prosigns to their code, for use with
+// morse.EncoderOptions.Prosigns and morse.DecoderOptions.Prosigns, e.g.
+//
+//	morse.NewDecoderWithOptions(r, morse.DecoderOptions{Prosigns: prosign.Names})
+var Names = make(map[string]morse.Code)
+
+// Maps the string of each prosign's code to its indices in registry
+var codeIndex = make(map[string][]int, len(registry))
+
+func init() {
+	for i, p := range registry {
+		Prosigns = append(Prosigns, p.Code)
+		codeIndex[p.Code.String()] = append(codeIndex[p.Code.String()], i)
+		if _, ok := Names[p.Notation]; p.RunTogether && !ok {
+			Names[p.Notation] = p.Code
+		}
+	}
+}
+
+// All returns all the prosigns defined in the package. Where prosigns have the
+// same notation or code, the run-together prosign with the most common meaning is first
+func All() []Prosign {
+	return append([]Prosign(nil), registry...)
+}
+
+// Lookup returns the run-together prosign with the given code. Abbreviations
+// that are sent as separate letters aren't returned, as their code is the same
+// as the letters (e.g. Rodger is just R), see LookupAll
+func Lookup(c morse.Code) (Prosign, bool) {
+	for _, i := range codeIndex[c.String()] {
+		if registry[i].RunTogether {
+			return registry[i], true
+		}
+	}
+	return Prosign{}, false
+}
+
+// LookupAll returns every prosign with the given code, including abbreviations
+// that are sent as separate letters, with the most common meaning first
+func LookupAll(c morse.Code) []Prosign {
+	indices := codeIndex[c.String()]
+	if len(indices) == 0 {
+		return nil
+	}
+	prosigns := make([]Prosign, len(indices))
+	for i, index := range indices {
+		prosigns[i] = registry[index]
+	}
+	return prosigns
+}
+
+// ByName returns the prosign with the given notation (e.g. "SK") or name
+// (e.g. "EndOfContact"), ignoring case. Angle brackets around the notation
+// (e.g. "<SK>") are also ignored
+func ByName(name string) (Prosign, bool) {
+	name = strings.TrimSuffix(strings.TrimPrefix(name, "<"), ">")
+	for _, p := range registry {
+		if strings.EqualFold(p.Notation, name) {
+			return p, true
+		}
+	}
+	for _, p := range registry {
+		if strings.EqualFold(p.Name, name) {
+			return p, true
+		}
+	}
+	return Prosign{}, false
+}
@@ -0,0 +1,62 @@
+package prosign
+
+import (
+	"github.com/bhollier/morse"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestRegistry(t *testing.T) {
+	a := assert.New(t)
+
+	p, ok := ByName("SK")
+	a.True(ok)
+	a.Equal("EndOfContact", p.Name)
+	a.True(p.RunTogether)
+	a.Equal(EndOfContact.String(), p.Code.String())
+
+	p, ok = ByName("<ar>")
+	a.True(ok)
+	a.Equal("Out", p.Name)
+
+	p, ok = ByName("AllBetween")
+	a.True(ok)
+	a.Equal("BN", p.Notation)
+
+	p, ok = Lookup(morse.JoinSignals(morse.B, morse.T))
+	a.True(ok)
+	a.Equal("Break", p.Name)
+
+	_, ok = Lookup(morse.JoinSignals(morse.Q, morse.Q))
+	a.False(ok)
+
+	// Plain letters aren't prosigns, unless all the matches are asked for
+	for _, c := range []morse.Code{morse.R, morse.K, morse.C, morse.N} {
+		_, ok = Lookup(c)
+		a.False(ok, c.String())
+		a.Len(LookupAll(c), 1, c.String())
+	}
+	a.Equal("Rodger", LookupAll(morse.R)[0].Name)
+	a.Nil(LookupAll(morse.Q))
+
+	// The common meaning comes first
+	p, _ = Lookup(morse.JoinSignals(morse.A, morse.A))
+	a.Equal("Newline", p.Name)
+	p, _ = ByName("AA")
+	a.Equal("Newline", p.Name)
+	all := LookupAll(morse.JoinSignals(morse.A, morse.A))
+	if a.Len(all, 2) {
+		a.Equal("UnknownStation", all[1].Name)
+	}
+
+	// Each prosign should only be in the registry once
+	names := make(map[string]bool)
+	for _, p := range All() {
+		a.False(names[p.Name], p.Name)
+		names[p.Name] = true
+	}
+	a.Len(Prosigns, len(All()))
+
+	a.Equal(Out.String(), Names["AR"].String())
+	a.NotContains(Names, "DE")
+}
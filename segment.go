@@ -0,0 +1,239 @@
+package morse
+
+import (
+	"math"
+	"sort"
+	"strings"
+)
+
+// Segmentation is one way of splitting a word of run-together code into
+// characters, see Segment
+type Segmentation struct {
+	// Text is the decoded (uppercase) text of the characters
+	Text string
+	// Code is the word with a RuneSpace between each of the characters
+	Code Code
+	// Score is how likely the segmentation is, higher being more likely
+	Score float64
+}
+
+// Scorer scores how likely some decoded text is, higher being more likely
+type Scorer func(text string) float64
+
+// SegmentOptions configures Segment. The zero value is the default configuration
+type SegmentOptions struct {
+	// Dictionary is used to look up the characters.
+	// If nil, DefaultDictionary is used
+	Dictionary *Dictionary
+
+	// Tree is the Tree of the Dictionary, which can be given to avoid building it
+	// on every call when segmenting many words. If nil, it is built from the Dictionary
+	Tree *Tree
+
+	// Scorer is used to rank the segmentations.
+	// If nil, LetterFrequencyScore is used
+	Scorer Scorer
+
+	// Limit is the maximum number of segmentations to return, or 0 for all of them
+	Limit int
+
+	// MaxSegmentations is the maximum number of segmentations that are found
+	// before ranking by the Scorer, as long words can be split in a huge number
+	// of ways. The segmentations found are the best by LetterFrequencyScore.
+	// If 0, DefaultMaxSegmentations is used
+	MaxSegmentations int
+}
+
+// DefaultMaxSegmentations is the default SegmentOptions.MaxSegmentations
+const DefaultMaxSegmentations = 10000
+
+// Segment lists the ways that a word of code, where some of the RuneSpaces between
+// characters were sent as SignalSpaces (e.g. by a sloppy fist), can be split into
+// characters of the Dictionary, ranked by the Scorer (most likely first).
+// Any RuneSpaces in the word are kept, so only the SignalSpaces are split at
+func Segment(word Code, opts SegmentOptions) []Segmentation {
+	if opts.Dictionary == nil {
+		opts.Dictionary = DefaultDictionary
+	}
+	if opts.Scorer == nil {
+		opts.Scorer = LetterFrequencyScore
+	}
+	if opts.MaxSegmentations == 0 {
+		opts.MaxSegmentations = DefaultMaxSegmentations
+	}
+	tree := opts.Tree
+	if tree == nil {
+		tree = NewTree(opts.Dictionary)
+	}
+
+	// The index in the word of each of the signals that make up the
+	// characters (i.e. not including SignalSpaces and RuneSpaces)
+	var signals []int
+	// Whether the word can be split after each of the signals, and
+	// whether it must be (as it's followed by a RuneSpace)
+	var splittable, mustSplit []bool
+	for i, s := range word {
+		if s == SignalSpace || (!s.Audible() && s.DitDuration() >= RuneSpace.DitDuration()) {
+			if len(signals) > 0 {
+				splittable[len(signals)-1] = true
+				mustSplit[len(signals)-1] = mustSplit[len(signals)-1] || s != SignalSpace
+			}
+			continue
+		}
+		signals = append(signals, i)
+		splittable = append(splittable, false)
+		mustSplit = append(mustSplit, false)
+	}
+	if len(signals) == 0 {
+		return nil
+	}
+
+	// The lattice of characters, where edges[i] are the characters starting at signal i
+	type edge struct {
+		start, end int
+		text       string
+		score      float64
+	}
+	edges := make([][]edge, len(signals))
+	for start := range signals {
+		n := tree.root
+		for end := start; end < len(signals); end++ {
+			if n = n.child(word[signals[end]]); n == nil {
+				break
+			}
+			last := end+1 == len(signals)
+			if n.ok && (last || splittable[end]) {
+				text := strings.ToUpper(n.text)
+				edges[start] = append(edges[start], edge{start, end + 1, text, LetterFrequencyScore(text)})
+			}
+			// A RuneSpace can't be within a character
+			if mustSplit[end] {
+				break
+			}
+		}
+	}
+
+	// Whether the end of the word can be reached from each signal, to prune dead ends
+	reachable := make([]bool, len(signals)+1)
+	reachable[len(signals)] = true
+	for start := len(signals) - 1; start >= 0; start-- {
+		for _, e := range edges[start] {
+			if reachable[e.end] {
+				reachable[start] = true
+				break
+			}
+		}
+	}
+
+	// Find the best segmentations by LetterFrequencyScore, which is the sum of the
+	// scores of the characters, so only the best MaxSegmentations of the paths
+	// that end at each signal need to be kept
+	type path struct {
+		prev  *path
+		last  edge
+		score float64
+	}
+	paths := make([][]*path, len(signals)+1)
+	paths[0] = []*path{nil}
+	for start := range signals {
+		if !reachable[start] {
+			continue
+		}
+		sort.SliceStable(paths[start], func(i, j int) bool {
+			return paths[start][i].score > paths[start][j].score
+		})
+		if len(paths[start]) > opts.MaxSegmentations {
+			paths[start] = paths[start][:opts.MaxSegmentations]
+		}
+		for _, p := range paths[start] {
+			score := 0.0
+			if p != nil {
+				score = p.score
+			}
+			for _, e := range edges[start] {
+				if reachable[e.end] {
+					paths[e.end] = append(paths[e.end], &path{prev: p, last: e, score: score + e.score})
+				}
+			}
+		}
+	}
+	ends := paths[len(signals)]
+	sort.SliceStable(ends, func(i, j int) bool {
+		return ends[i].score > ends[j].score
+	})
+	if len(ends) > opts.MaxSegmentations {
+		ends = ends[:opts.MaxSegmentations]
+	}
+
+	segmentations := make([]Segmentation, 0, len(ends))
+	for _, p := range ends {
+		var characters []edge
+		for ; p != nil; p = p.prev {
+			characters = append(characters, p.last)
+		}
+		sb := strings.Builder{}
+		code := make(Code, 0, len(word)+len(characters))
+		for i := len(characters) - 1; i >= 0; i-- {
+			e := characters[i]
+			if len(code) > 0 {
+				code = append(code, RuneSpace)
+			}
+			code = append(code, word[signals[e.start]:signals[e.end-1]+1]...)
+			sb.WriteString(e.text)
+		}
+		text := sb.String()
+		segmentations = append(segmentations, Segmentation{Text: text, Code: code, Score: opts.Scorer(text)})
+	}
+	sort.SliceStable(segmentations, func(i, j int) bool {
+		return segmentations[i].Score > segmentations[j].Score
+	})
+	if opts.Limit > 0 && len(segmentations) > opts.Limit {
+		segmentations = segmentations[:opts.Limit]
+	}
+	return segmentations
+}
+
+// The relative frequency of each letter in English text
+var letterFrequencies = map[rune]float64{
+	'a': 0.082, 'b': 0.015, 'c': 0.028, 'd': 0.043, 'e': 0.127, 'f': 0.022, 'g': 0.020,
+	'h': 0.061, 'i': 0.070, 'j': 0.0015, 'k': 0.0077, 'l': 0.040, 'm': 0.024, 'n': 0.067,
+	'o': 0.075, 'p': 0.019, 'q': 0.00095, 'r': 0.060, 's': 0.063, 't': 0.091, 'u': 0.028,
+	'v': 0.0098, 'w': 0.024, 'x': 0.0015, 'y': 0.020, 'z': 0.00074,
+}
+
+// The frequency used for runes that aren't letters
+const otherFrequency = 0.0005
+
+// LetterFrequencyScore is a Scorer which scores the text by the frequency of
+// its letters in English, as the sum of their log frequencies
+func LetterFrequencyScore(text string) float64 {
+	score := 0.0
+	for _, r := range strings.ToLower(text) {
+		f, ok := letterFrequencies[r]
+		if !ok {
+			f = otherFrequency
+		}
+		score += math.Log(f)
+	}
+	return score
+}
+
+// The score added to words by WordListScorer, which is larger than the
+// (negative) LetterFrequencyScore of any reasonable word, so listed words rank first
+const wordListBonus = 1000
+
+// WordListScorer creates a Scorer which ranks text that is in the given list of
+// words (e.g. from the words package) above text that isn't, then by LetterFrequencyScore
+func WordListScorer(words []string) Scorer {
+	wordSet := make(map[string]struct{}, len(words))
+	for _, word := range words {
+		wordSet[strings.ToLower(word)] = struct{}{}
+	}
+	return func(text string) float64 {
+		score := LetterFrequencyScore(text)
+		if _, ok := wordSet[strings.ToLower(text)]; ok {
+			score += wordListBonus
+		}
+		return score
+	}
+}
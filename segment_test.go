@@ -0,0 +1,79 @@
+package morse
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func segmentationTexts(segmentations []Segmentation) []string {
+	texts := make([]string, len(segmentations))
+	for i, s := range segmentations {
+		texts[i] = s.Text
+	}
+	return texts
+}
+
+func TestSegment(t *testing.T) {
+	a := assert.New(t)
+
+	// ・・－ can be U, IT, EA or EET
+	segmentations := Segment(JoinSignals(E, E, T), SegmentOptions{})
+	a.ElementsMatch([]string{"U", "IT", "EA", "EET"}, segmentationTexts(segmentations))
+	for i := 1; i < len(segmentations); i++ {
+		a.GreaterOrEqual(segmentations[i-1].Score, segmentations[i].Score)
+	}
+	for _, s := range segmentations {
+		a.Equal(s.Text, Decode(s.Code))
+	}
+
+	// Rune spaces are kept
+	a.ElementsMatch([]string{"EI", "EEE"},
+		segmentationTexts(Segment(JoinLetters(E, JoinSignals(E, E)), SegmentOptions{})))
+
+	// Codes that can't be split into characters have no segmentations
+	a.Empty(Segment(Code{Dah, SignalSpace, Dah, SignalSpace, Dah, SignalSpace, Dah, SignalSpace, Dah, SignalSpace, Dah, RuneSpace, LongDah}, SegmentOptions{}))
+	a.Empty(Segment(Code{}, SegmentOptions{}))
+}
+
+func TestSegment_Ranking(t *testing.T) {
+	a := assert.New(t)
+
+	// "the" sent without rune spaces
+	word := JoinSignals(T, H, E)
+	segmentations := Segment(word, SegmentOptions{Limit: 3})
+	a.Len(segmentations, 3)
+
+	segmentations = Segment(word, SegmentOptions{Scorer: WordListScorer([]string{"the", "be"})})
+	a.Equal("THE", segmentations[0].Text)
+	a.Equal(JoinLetters(T, H, E), segmentations[0].Code)
+
+	segmentations = Segment(JoinSignals(S, O, S), SegmentOptions{Scorer: WordListScorer([]string{"sos"})})
+	a.Equal("SOS", segmentations[0].Text)
+
+	a.Len(Segment(JoinSignals(S, O, S, S, O, S), SegmentOptions{MaxSegmentations: 10}), 10)
+}
+
+func TestSegment_MaxSegmentations(t *testing.T) {
+	a := assert.New(t)
+
+	// The best segmentations are found, however few are kept
+	word := JoinSignals(S, O, S, S, O, S)
+	all := Segment(word, SegmentOptions{})
+	best := Segment(word, SegmentOptions{MaxSegmentations: 3})
+	a.Equal(all[:3], best)
+
+	// A long word is split without finding every segmentation
+	long := JoinSignals(T, H, E, Q, U, I, C, K, B, R, O, W, N, F, O, X)
+	segmentations := Segment(long, SegmentOptions{Limit: 1, MaxSegmentations: 100})
+	a.Len(segmentations, 1)
+	a.Equal(LetterFrequencyScore(segmentations[0].Text), segmentations[0].Score)
+}
+
+func TestSegment_Tree(t *testing.T) {
+	a := assert.New(t)
+
+	word := JoinSignals(T, H, E)
+	a.Equal(Segment(word, SegmentOptions{}), Segment(word, SegmentOptions{Tree: NewTree(DefaultDictionary)}))
+	a.Equal(Segment(word, SegmentOptions{Dictionary: American}),
+		Segment(word, SegmentOptions{Tree: NewTree(American)}))
+}
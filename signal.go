@@ -92,6 +92,12 @@ func (s Signal) String() string {
 		return " "
 	case WordSpace:
 		return "  "
+	case LongDah:
+		return "⸺"
+	case ExtraLongDah:
+		return "⸻"
+	case InternalSpace:
+		return "\u2009"
 	default:
 		return "?"
 	}
@@ -145,47 +151,12 @@ const StandardWord = "PARIS"
 // StandardWordCode is the Morse Code of StandardWord, including the WordSpace on the end
 var StandardWordCode = append(Join([]Code{P, A, R, I, S}, Code{RuneSpace}), WordSpace)
 
-// The duration of the standard word, where 1 = the length of a dit
-var standardWordDuration = StandardWordCode.DitDuration()
-
-// The duration of the dits, dahs and rune spaces in the standard word.
-// Used to calculate Farnsworth timing
-var standardWordRuneDuration uint
-
-// The duration of the spaces in the standard word (between letters and words).
-// Used to calculate Farnsworth timing
-var standardWordSpaceDuration uint
-
-func init() {
-	for _, s := range StandardWordCode {
-		d := s.DitDuration()
-		if s == Dit || s == Dah || s == SignalSpace {
-			standardWordRuneDuration += d
-		} else if s == RuneSpace || s == WordSpace {
-			standardWordSpaceDuration += d
-		} else {
-			panic("this shouldn't be possible")
-		}
-	}
-}
-
 // Duration returns the duration of the signal, at the given WPM.
 // The standard word for the WPM is "PARIS".
 //
 // If farnsworthWPM is non-zero, the duration is based on Farnsworth timing,
-// see Code.Duration for more info
+// see Code.Duration for more info. Signals only used by American Morse use
+// AmericanTiming, see Code.Duration
 func (s Signal) Duration(wpm, farnsworthWPM uint) time.Duration {
-	if farnsworthWPM > wpm {
-		panic(fmt.Errorf("farnswordWPM (%d) > wpm (%d)", farnsworthWPM, wpm))
-	}
-
-	ditDuration := time.Minute / time.Duration(standardWordDuration*wpm)
-	if farnsworthWPM == 0 || (s != RuneSpace && s != WordSpace) {
-		return time.Duration(s.DitDuration()) * ditDuration
-	} else {
-		farnsworthDitDuration := ((time.Minute / time.Duration(farnsworthWPM)) -
-			(time.Duration(standardWordRuneDuration) * ditDuration)) /
-			time.Duration(standardWordSpaceDuration)
-		return time.Duration(s.DitDuration()) * farnsworthDitDuration
-	}
+	return s.DurationWithTiming(wpm, farnsworthWPM, Code{s}.timing())
 }
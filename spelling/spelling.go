@@ -0,0 +1,292 @@
+// Package spelling corrects the spelling of decoded Morse, by comparing each
+// word with a list of words (by default the words package). Corrections are
+// weighted by how easy it is to mistake one character for another in Morse,
+// so e.g. confusing E and T (・ and －) or S and H (・・・ and ・・・・)
+// costs less than substituting characters that sound nothing alike
+package spelling
+
+import (
+	"github.com/bhollier/morse"
+	"github.com/bhollier/morse/words"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// The cost of inserting or deleting a character is based on the number of
+// signals in its code, as e.g. noise is more likely to be decoded as an E
+// than a Q. This is the cost of a character without signals, and
+// the maximum cost of any character
+const (
+	insertDeleteBaseCost = 0.5
+	insertDeleteMaxCost  = 1.5
+)
+
+// The cost of inserting or deleting each signal in a character's code
+const insertDeleteSignalCost = 0.25
+
+// The cost of substituting a character that wasn't decoded ('?')
+const unknownCost = 0.5
+
+// The cost of keeping a word that isn't in the list of words as it is,
+// as the list of words doesn't contain every word (e.g. plurals)
+const keepCost = 1.0
+
+// The temperature of the softmax used to calculate the
+// confidence of a suggestion from its cost
+const confidenceTemperature = 0.25
+
+// DefaultMaxCost is the default Corrector.MaxCost
+const DefaultMaxCost = 1.5
+
+// DefaultMinConfidence is the default Corrector.MinConfidence
+const DefaultMinConfidence = 0.7
+
+// Suggestion is a word that a decoded word may have been meant to be
+type Suggestion struct {
+	Word string
+	// Cost is the Morse-aware edit distance from the decoded word
+	Cost float64
+	// Confidence is how likely (from 0 to 1) the suggestion is compared
+	// to the next best suggestion, and to keeping the word as it is
+	Confidence float64
+}
+
+// Correction is a decoded word that was corrected
+type Correction struct {
+	// Index is the index of the word in the text
+	Index     int
+	Original  string
+	Corrected string
+	// Confidence is the confidence of the suggestion that was applied
+	Confidence float64
+}
+
+// Result is the result of correcting some decoded text
+type Result struct {
+	// Raw is the text as it was decoded
+	Raw string
+	// Text is the text with the corrections applied
+	Text        string
+	Corrections []Correction
+}
+
+// Corrector suggests and applies corrections to decoded words
+type Corrector struct {
+	// MaxCost is the maximum cost of a suggestion
+	MaxCost float64
+	// MinConfidence is the minimum confidence a suggestion
+	// needs to be applied by Correct
+	MinConfidence float64
+
+	dict    *morse.Dictionary
+	words   []string
+	wordSet map[string]struct{}
+}
+
+// New creates a Corrector for the words package's list of
+// English words, sent with morse.DefaultDictionary
+func New() *Corrector {
+	return NewCorrector(words.All(), morse.DefaultDictionary)
+}
+
+// NewCorrector creates a Corrector for the given list of words,
+// where the characters are sent with the codes in dict
+func NewCorrector(wordList []string, dict *morse.Dictionary) *Corrector {
+	c := &Corrector{
+		MaxCost:       DefaultMaxCost,
+		MinConfidence: DefaultMinConfidence,
+		dict:          dict,
+		words:         make([]string, 0, len(wordList)),
+		wordSet:       make(map[string]struct{}, len(wordList)),
+	}
+	for _, word := range wordList {
+		word = strings.ToLower(word)
+		if _, ok := c.wordSet[word]; !ok {
+			c.wordSet[word] = struct{}{}
+			c.words = append(c.words, word)
+		}
+	}
+	return c
+}
+
+// Suggest returns up to n words (or all, if n is 0) that the decoded word may
+// have been meant to be, most likely first. If the word is already in the list
+// of words, or can't be corrected (e.g. it contains digits), returns nil
+func (c *Corrector) Suggest(word string, n int) []Suggestion {
+	word = strings.ToLower(word)
+	if _, ok := c.wordSet[word]; ok || !correctable(word) {
+		return nil
+	}
+	rs := []rune(word)
+
+	var suggestions []Suggestion
+	for _, candidate := range c.words {
+		candidateRunes := []rune(candidate)
+		// The cost is at least the difference in length
+		if math.Abs(float64(len(candidateRunes)-len(rs)))*insertDeleteBaseCost > c.MaxCost {
+			continue
+		}
+		if cost := c.distance(rs, candidateRunes); cost <= c.MaxCost {
+			suggestions = append(suggestions, Suggestion{Word: candidate, Cost: cost})
+		}
+	}
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		if suggestions[i].Cost != suggestions[j].Cost {
+			return suggestions[i].Cost < suggestions[j].Cost
+		}
+		return suggestions[i].Word < suggestions[j].Word
+	})
+
+	// Convert the costs into confidences with a softmax of the suggestion,
+	// the next best suggestion and keeping the word as it is
+	weight := func(cost float64) float64 {
+		return math.Exp(-cost / confidenceTemperature)
+	}
+	for i := range suggestions {
+		total := weight(suggestions[i].Cost) + weight(keepCost)
+		if i == 0 && len(suggestions) > 1 {
+			total += weight(suggestions[1].Cost)
+		} else if i > 0 {
+			total += weight(suggestions[0].Cost)
+		}
+		suggestions[i].Confidence = weight(suggestions[i].Cost) / total
+	}
+	if n > 0 && len(suggestions) > n {
+		suggestions = suggestions[:n]
+	}
+	return suggestions
+}
+
+// Correct applies the most likely suggestion to each of the words of the decoded
+// text, if its confidence is at least MinConfidence. The corrected words keep the
+// case of the decoded text (i.e. uppercase for text from a morse.Decoder), and any
+// punctuation around them. The spaces and newlines between words are kept as-is
+func (c *Corrector) Correct(text string) Result {
+	result := Result{Raw: text}
+	sb := strings.Builder{}
+	index := 0
+	for len(text) > 0 {
+		// Copy the spaces before the word
+		start := strings.IndexFunc(text, func(r rune) bool { return !unicode.IsSpace(r) })
+		if start < 0 {
+			sb.WriteString(text)
+			break
+		}
+		sb.WriteString(text[:start])
+		text = text[start:]
+		end := strings.IndexFunc(text, unicode.IsSpace)
+		if end < 0 {
+			end = len(text)
+		}
+		field := text[:end]
+		text = text[end:]
+
+		// Only correct the word, without the punctuation around it
+		prefix, word, suffix := trimPunctuation(field)
+		sb.WriteString(prefix)
+		if correction, ok := c.correct(word, index); ok {
+			result.Corrections = append(result.Corrections, correction)
+			word = correction.Corrected
+		}
+		sb.WriteString(word)
+		sb.WriteString(suffix)
+		index++
+	}
+	result.Text = sb.String()
+	return result
+}
+
+// correct returns the correction of the word (at the given index
+// in the text), or false if it shouldn't be corrected
+func (c *Corrector) correct(word string, index int) (Correction, bool) {
+	if word == "" {
+		return Correction{}, false
+	}
+	suggestions := c.Suggest(word, 1)
+	if len(suggestions) == 0 || suggestions[0].Confidence < c.MinConfidence {
+		return Correction{}, false
+	}
+	corrected := suggestions[0].Word
+	if strings.ToUpper(word) == word {
+		corrected = strings.ToUpper(corrected)
+	}
+	return Correction{
+		Index:      index,
+		Original:   word,
+		Corrected:  corrected,
+		Confidence: suggestions[0].Confidence,
+	}, true
+}
+
+// trimPunctuation splits the punctuation at the start and end of the
+// field from the word. Unknown characters ('?') are part of the word
+func trimPunctuation(field string) (prefix, word, suffix string) {
+	punctuation := func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '?'
+	}
+	word = strings.TrimLeftFunc(field, punctuation)
+	prefix = field[:len(field)-len(word)]
+	word = strings.TrimRightFunc(word, punctuation)
+	suffix = field[len(prefix)+len(word):]
+	return prefix, word, suffix
+}
+
+// correctable returns whether the word is made up of only letters and unknown characters
+func correctable(word string) bool {
+	for _, r := range word {
+		if !unicode.IsLetter(r) && r != '?' {
+			return false
+		}
+	}
+	return true
+}
+
+// distance is the edit distance between the words, where the
+// cost of substituting a character is based on their codes
+func (c *Corrector) distance(a, b []rune) float64 {
+	prev := make([]float64, len(b)+1)
+	cur := make([]float64, len(b)+1)
+	for j := 1; j <= len(b); j++ {
+		prev[j] = prev[j-1] + c.insertDeleteCost(b[j-1])
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = prev[0] + c.insertDeleteCost(a[i-1])
+		for j := 1; j <= len(b); j++ {
+			cur[j] = math.Min(
+				math.Min(prev[j]+c.insertDeleteCost(a[i-1]), cur[j-1]+c.insertDeleteCost(b[j-1])),
+				prev[j-1]+c.substituteCost(a[i-1], b[j-1]))
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+// insertDeleteCost is the cost of inserting or deleting the
+// character, which is based on the number of signals in its code
+func (c *Corrector) insertDeleteCost(r rune) float64 {
+	code := c.dict.FromRune(r)
+	if code == nil {
+		return insertDeleteMaxCost
+	}
+	return math.Min(insertDeleteMaxCost, insertDeleteBaseCost+insertDeleteSignalCost*morse.Distance(nil, code))
+}
+
+// substituteCost is the cost of decoding a when b was meant. It's
+// based on the morse.Distance of their codes, so is less than 1 for
+// characters that are easy to mistake for each other
+func (c *Corrector) substituteCost(a, b rune) float64 {
+	if a == b {
+		return 0
+	}
+	if a == '?' {
+		return unknownCost
+	}
+	aCode, bCode := c.dict.FromRune(a), c.dict.FromRune(b)
+	if aCode == nil || bCode == nil {
+		return insertDeleteMaxCost
+	}
+	d := morse.Distance(aCode, bCode)
+	return d / (d + 1)
+}
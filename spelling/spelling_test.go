@@ -0,0 +1,73 @@
+package spelling
+
+import (
+	"github.com/bhollier/morse"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCorrector_Suggest(t *testing.T) {
+	a := assert.New(t)
+
+	c := New()
+	suggestions := c.Suggest("EIME", 3)
+	if a.Len(suggestions, 3) {
+		a.Equal("time", suggestions[0].Word)
+		a.Greater(suggestions[0].Confidence, DefaultMinConfidence)
+		a.Less(suggestions[0].Cost, suggestions[1].Cost)
+	}
+
+	// Words in the list, or with digits, aren't corrected
+	a.Nil(c.Suggest("TIME", 0))
+	a.Nil(c.Suggest("73", 0))
+
+	// Mistaking S for H is more likely than for C, and E for T than for Q
+	c = NewCorrector([]string{"cat", "hat", "tea", "qea"}, morse.DefaultDictionary)
+	suggestions = c.Suggest("SAT", 0)
+	if a.Len(suggestions, 2) {
+		a.Equal("hat", suggestions[0].Word)
+		a.Equal("cat", suggestions[1].Word)
+	}
+	suggestions = c.Suggest("EEA", 1)
+	if a.Len(suggestions, 1) {
+		a.Equal("tea", suggestions[0].Word)
+	}
+
+	// An unknown character is cheap to substitute, but ambiguous
+	suggestions = c.Suggest("?AT", 2)
+	if a.Len(suggestions, 2) {
+		a.ElementsMatch([]string{"cat", "hat"}, []string{suggestions[0].Word, suggestions[1].Word})
+		a.Equal(suggestions[0].Cost, suggestions[1].Cost)
+		a.Less(suggestions[0].Confidence, DefaultMinConfidence)
+	}
+}
+
+func TestCorrector_Correct(t *testing.T) {
+	a := assert.New(t)
+
+	raw := morse.Decode(morse.FromText("eime is ?at 73"))
+	a.Equal("EIME IS ?AT 73", raw)
+
+	result := New().Correct(raw)
+	a.Equal(raw, result.Raw)
+	a.Equal("TIME IS ?AT 73", result.Text)
+	if a.Len(result.Corrections, 1) {
+		a.Equal(0, result.Corrections[0].Index)
+		a.Equal("EIME", result.Corrections[0].Original)
+		a.Equal("TIME", result.Corrections[0].Corrected)
+	}
+
+	// Lowercase text stays lowercase
+	a.Equal("the signal", New().Correct("tfe signah").Text)
+
+	// The separators and punctuation around words are kept
+	result = New().Correct("EIME\nIS,  HERE")
+	a.Equal("TIME\nIS,  HERE", result.Text)
+	result = New().Correct("\"EIME,\" IS (HERE)\n")
+	a.Equal("\"TIME,\" IS (HERE)\n", result.Text)
+	if a.Len(result.Corrections, 1) {
+		a.Equal(0, result.Corrections[0].Index)
+		a.Equal("EIME", result.Corrections[0].Original)
+	}
+	a.Equal(" ... ", New().Correct(" ... ").Text)
+}
@@ -0,0 +1,186 @@
+package morse
+
+import (
+	"bytes"
+	"github.com/bhollier/morse/internal/buffer"
+)
+
+// The number of signals a StreamDecoder reads at a time
+const streamDecoderBufferSize = 64
+
+// StreamDecoder converts Morse Code into human-readable text with low latency,
+// for live sources such as a NonBlockingChannelReader. Unlike Decoder, which
+// waits for a whole word, each character is decoded as soon as the RuneSpace
+// after it arrives, and the space between words as soon as the WordSpace does
+// (unless the input ends straight after it). If the Dictionary has a Composer
+// (e.g. Korean), the text of a word is held back until the word ends, so that it
+// can be composed (e.g. jamo into syllables).
+//
+// Read doesn't block (unless the Reader does), so it returns 0 bytes and a nil
+// error if the signals available so far don't complete any text. Don't use
+// io.ReadAll with a non-blocking source, as it would spin. Instead, poll and
+// back off when there is nothing to read:
+//
+//	for {
+//		n, err := d.Read(buf)
+//		if err != nil {
+//			break
+//		} else if n == 0 {
+//			time.Sleep(10 * time.Millisecond)
+//			continue
+//		}
+//		fmt.Print(string(buf[:n]))
+//	}
+type StreamDecoder struct {
+	charDecoder
+	r        Reader
+	signals  []Signal
+	overflow buffer.Overflow[byte]
+	// The signals of the current character
+	char Code
+	// The text decoded since the last shift or word space, which
+	// is held back to be composed if the Dictionary has a Composer
+	segment bytes.Buffer
+	// The number of signals read, and the offset of the current character
+	offset     int
+	charOffset int
+	// Whether a character has been output since the last word space,
+	// and the number of characters output in the word
+	inWord    bool
+	character int
+	// The error from r, returned once the output is emptied
+	err error
+}
+
+// NewStreamDecoder creates a StreamDecoder from the given Reader
+func NewStreamDecoder(r Reader) *StreamDecoder {
+	return NewStreamDecoderWithOptions(r, DecoderOptions{})
+}
+
+// NewStreamDecoderWithOptions creates a StreamDecoder from the given Reader, configured by opts
+func NewStreamDecoderWithOptions(r Reader, opts DecoderOptions) *StreamDecoder {
+	return &StreamDecoder{
+		charDecoder: newCharDecoder(opts),
+		r:           r,
+		signals:     make([]Signal, streamDecoderBufferSize),
+	}
+}
+
+func (d *StreamDecoder) Read(b []byte) (n int, err error) {
+	// First, try to empty the overflow from the last read
+	n = d.overflow.Empty(b)
+	b = b[n:]
+
+	if d.err != nil {
+		if n > 0 {
+			return n, nil
+		}
+		return 0, d.err
+	}
+	if len(b) == 0 {
+		return
+	}
+
+	out := bytes.Buffer{}
+	shifts := d.opts.Dictionary.Shifts()
+	// Whether the text ends with the space between words
+	endsWithSpace := func() bool {
+		return !d.inWord && out.Len() > 0 && out.Bytes()[out.Len()-1] == ' '
+	}
+	// Keep reading while the source has signals but they don't complete any text,
+	// or (without waiting) to find out if the input ends after a word space
+	for d.err == nil {
+		signalsRead, err := d.r.Read(d.signals)
+		for _, s := range d.signals[:signalsRead] {
+			d.offset++
+			if s.Audible() || s.DitDuration() < RuneSpace.DitDuration() {
+				if len(d.char) == 0 {
+					// Ignore any spaces before the character
+					if !s.Audible() {
+						continue
+					}
+					d.charOffset = d.offset - 1
+				}
+				d.char = append(d.char, s)
+				continue
+			}
+
+			if d.err = d.flushChar(&out, shifts); d.err != nil {
+				break
+			}
+			if s.DitDuration() >= WordSpace.DitDuration() && d.inWord {
+				d.flushSegment(&out)
+				out.WriteByte(' ')
+				d.inWord = false
+				d.character = 0
+			}
+		}
+		if err != nil && d.err == nil {
+			// The character is complete at the end of the input
+			if d.err = d.flushChar(&out, shifts); d.err == nil {
+				d.err = err
+			}
+		}
+		if d.err != nil {
+			d.flushSegment(&out)
+			// Don't end the text with the space between words
+			if endsWithSpace() {
+				out.Truncate(out.Len() - 1)
+			}
+		}
+		if signalsRead == 0 || (out.Len() > 0 && !endsWithSpace()) {
+			break
+		}
+	}
+
+	bytesCopied := d.overflow.Copy(b, out.Bytes())
+	n += bytesCopied
+	if n == 0 && d.err != nil {
+		return 0, d.err
+	}
+	return n, nil
+}
+
+// flushChar writes the text of the current character (if there is one),
+// returning a *DecodeError if it isn't known and the decoder is strict
+func (d *StreamDecoder) flushChar(out *bytes.Buffer, shifts []Shift) error {
+	// Trim any trailing spaces
+	for len(d.char) > 0 && !d.char[len(d.char)-1].Audible() {
+		d.char = d.char[:len(d.char)-1]
+	}
+	if len(d.char) == 0 {
+		return nil
+	}
+	defer func() {
+		d.char = d.char[:0]
+	}()
+
+	if shift, ok := d.shiftFromCode(d.char, shifts); ok {
+		d.flushSegment(out)
+		d.shift = shift
+		return nil
+	}
+	if !d.inWord {
+		d.stats.Words++
+		d.inWord = true
+	}
+	if !d.writeCharacter(&d.segment, d.char) && d.opts.Strict {
+		return &DecodeError{
+			Code:      append(Code(nil), d.char...),
+			Word:      d.stats.Words - 1,
+			Character: d.character,
+			Offset:    d.charOffset,
+		}
+	}
+	d.character++
+	if !d.dictionary().composes() {
+		d.flushSegment(out)
+	}
+	return nil
+}
+
+// flushSegment writes the composed text of the current segment
+func (d *StreamDecoder) flushSegment(out *bytes.Buffer) {
+	d.dictionary().composeTo(out, d.segment.Bytes())
+	d.segment.Reset()
+}
@@ -0,0 +1,124 @@
+package morse
+
+import (
+	"github.com/stretchr/testify/assert"
+	"io"
+	"testing"
+)
+
+func TestStreamDecoder(t *testing.T) {
+	a := assert.New(t)
+
+	c := make(chan Signal, 64)
+	d := NewStreamDecoder(ReaderFromChan(c, false))
+	buf := make([]byte, 16)
+	read := func() string {
+		n, err := d.Read(buf)
+		a.NoError(err)
+		return string(buf[:n])
+	}
+	send := func(signals ...Signal) {
+		for _, s := range signals {
+			c <- s
+		}
+	}
+
+	// Nothing is available yet
+	a.Equal("", read())
+
+	// A character isn't complete until the rune space
+	send(Dah, SignalSpace, Dah, SignalSpace)
+	a.Equal("", read())
+	send(Dah)
+	a.Equal("", read())
+	send(RuneSpace)
+	a.Equal("O", read())
+	send(JoinLetters(K, A)...)
+	a.Equal("K", read())
+
+	// The word space completes the character and the word
+	send(WordSpace)
+	a.Equal("A ", read())
+	send(JoinLetters(H, I)...)
+	send(RuneSpace)
+	a.Equal("HI", read())
+
+	// The last character is completed by the end of the input
+	send(WordSpace)
+	send(QuestionMark...)
+	close(c)
+	a.Equal(" ?", read())
+	n, err := d.Read(buf)
+	a.Equal(0, n)
+	a.Equal(io.EOF, err)
+}
+
+func TestStreamDecoder_ReadAll(t *testing.T) {
+	a := assert.New(t)
+
+	code := JoinWords(JoinLetters(S, O, S), JoinLetters(JoinSignals(A, R), WabunIn, FromCodeString("・－"), WabunOut, E))
+	b, err := io.ReadAll(NewStreamDecoderWithOptions(NewReader(code), DecoderOptions{
+		Prosigns: map[string]Code{"AR": JoinSignals(A, R)},
+	}))
+	a.NoError(err)
+	a.Equal("SOS <AR>イE", string(b))
+	a.Equal(Decode(FromText("the quick brown fox, 123")), streamDecode(FromText("the quick brown fox, 123")))
+
+	// A trailing word space doesn't produce a trailing space
+	a.Equal("SOS", streamDecode(append(JoinLetters(S, O, S), WordSpace)))
+}
+
+func TestStreamDecoder_Compose(t *testing.T) {
+	a := assert.New(t)
+
+	// The jamo of each word are composed once the word ends
+	code := FromTextWithDictionary("한국 말", Korean)
+	b, err := io.ReadAll(NewStreamDecoderWithOptions(NewReader(code), DecoderOptions{Dictionary: Korean}))
+	a.NoError(err)
+	a.Equal("한국 말", string(b))
+
+	c := make(chan Signal, 64)
+	d := NewStreamDecoderWithOptions(ReaderFromChan(c, false), DecoderOptions{Dictionary: Korean})
+	buf := make([]byte, 16)
+	for _, s := range append(FromTextWithDictionary("한", Korean), RuneSpace) {
+		c <- s
+	}
+	n, err := d.Read(buf)
+	a.NoError(err)
+	a.Equal("", string(buf[:n]))
+	for _, s := range append(Code{WordSpace}, FromTextWithDictionary("말", Korean)...) {
+		c <- s
+	}
+	close(c)
+	b, err = io.ReadAll(d)
+	a.NoError(err)
+	a.Equal("한 말", string(b))
+}
+
+func TestStreamDecoder_Strict(t *testing.T) {
+	a := assert.New(t)
+
+	code := JoinWords(JoinLetters(H, I), JoinLetters(A, JoinSignals(S, K), B))
+	d := NewStreamDecoderWithOptions(NewReader(code), DecoderOptions{Strict: true})
+	b, err := io.ReadAll(d)
+	a.Equal("HI A", string(b))
+	var decodeErr *DecodeError
+	if a.ErrorAs(err, &decodeErr) {
+		a.Equal(&DecodeError{Code: JoinSignals(S, K), Word: 1, Character: 1, Offset: 16}, decodeErr)
+	}
+	a.Equal(DecodeStats{Words: 2, Characters: 4, Unknown: 1}, d.Stats())
+
+	d = NewStreamDecoder(NewReader(code))
+	b, err = io.ReadAll(d)
+	a.NoError(err)
+	a.Equal("HI A?B", string(b))
+	a.Equal(DecodeStats{Words: 2, Characters: 5, Unknown: 1}, d.Stats())
+}
+
+func streamDecode(c Code) string {
+	b, err := io.ReadAll(NewStreamDecoder(NewReader(c)))
+	if err != nil {
+		panic(err)
+	}
+	return string(b)
+}
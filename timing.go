@@ -0,0 +1,146 @@
+package morse
+
+import (
+	"fmt"
+	"time"
+)
+
+// Timing determines how long each signal lasts relative to a dit, for variants of
+// Morse that are timed differently to International Morse (e.g. AmericanTiming).
+// Signals that the timing doesn't set (fields that are 0, and any other signals)
+// last their Signal.DitDuration, so the zero value is International Morse timing
+type Timing struct {
+	// The duration of a Dah
+	Dah uint
+
+	// The durations of the inaudible signals
+	InternalSpace, RuneSpace, WordSpace uint
+
+	// StandardWord is the code of the standard word that the WPM is based on,
+	// including the WordSpace on the end. If nil, StandardWordCode is used
+	StandardWord Code
+}
+
+// AmericanTiming is the conventional timing of American (railroad) Morse, where a
+// Dah lasts 2 dits and the space between words 6 dits. The other signals last their
+// own Signal.DitDuration: the LongDah of L 4 dits, the ExtraLongDah of zero 5 dits,
+// the InternalSpace within characters 2 dits and the space between characters 3
+// dits. The WPM is based on "PARIS" sent in American Morse. See American
+var AmericanTiming = Timing{
+	Dah:       2,
+	WordSpace: 6,
+	StandardWord: append(JoinLetters(americanCode("....."), americanCode(".-"),
+		americanCode(". .."), americanCode(".."), americanCode("...")), WordSpace),
+}
+
+// DitDuration returns the duration of the signal with the timing, relative to a Dit
+func (t Timing) DitDuration(s Signal) uint {
+	var d uint
+	switch s {
+	case Dah:
+		d = t.Dah
+	case InternalSpace:
+		d = t.InternalSpace
+	case RuneSpace:
+		d = t.RuneSpace
+	case WordSpace:
+		d = t.WordSpace
+	}
+	if d == 0 {
+		return s.DitDuration()
+	}
+	return d
+}
+
+// timing returns AmericanTiming if the code has a signal that is only used by
+// American Morse (LongDah, ExtraLongDah or InternalSpace), or International
+// Morse timing otherwise
+func (c Code) timing() Timing {
+	for _, s := range c {
+		if americanSignal(s) {
+			return AmericanTiming
+		}
+	}
+	return Timing{}
+}
+
+// standardWord returns the timing's StandardWord, or StandardWordCode if it isn't set
+func (t Timing) standardWord() Code {
+	if t.StandardWord == nil {
+		return StandardWordCode
+	}
+	return t.StandardWord
+}
+
+// spacing returns whether the signal is a space between characters or words,
+// which is lengthened by Farnsworth timing
+func spacing(s Signal) bool {
+	return !s.Audible() && s.DitDuration() >= RuneSpace.DitDuration()
+}
+
+// unitDurations returns the duration of a dit at the given WPM, and the duration
+// of a dit of the spaces between characters and words, which is longer when
+// using Farnsworth timing (see Code.Duration)
+func (t Timing) unitDurations(wpm, farnsworthWPM uint) (dit, spacingDit time.Duration) {
+	if farnsworthWPM > wpm {
+		panic(fmt.Errorf("farnswordWPM (%d) > wpm (%d)", farnsworthWPM, wpm))
+	}
+
+	// The duration of the standard word, split into the duration of the characters
+	// (including the spaces within them) and the spaces between them
+	var standardWordRuneDuration, standardWordSpaceDuration uint
+	for _, s := range t.standardWord() {
+		if spacing(s) {
+			standardWordSpaceDuration += t.DitDuration(s)
+		} else {
+			standardWordRuneDuration += t.DitDuration(s)
+		}
+	}
+
+	dit = time.Minute / time.Duration((standardWordRuneDuration+standardWordSpaceDuration)*wpm)
+	if farnsworthWPM == 0 {
+		return dit, dit
+	}
+	spacingDit = ((time.Minute / time.Duration(farnsworthWPM)) -
+		(time.Duration(standardWordRuneDuration) * dit)) /
+		time.Duration(standardWordSpaceDuration)
+	return dit, spacingDit
+}
+
+// duration returns the duration of the signal, given the durations from unitDurations
+func (t Timing) duration(s Signal, dit, spacingDit time.Duration) time.Duration {
+	if spacing(s) {
+		return time.Duration(t.DitDuration(s)) * spacingDit
+	}
+	return time.Duration(t.DitDuration(s)) * dit
+}
+
+// DurationWithTiming returns the duration of the signal with the given Timing, at
+// the given WPM. See Signal.Duration
+func (s Signal) DurationWithTiming(wpm, farnsworthWPM uint, t Timing) time.Duration {
+	dit, spacingDit := t.unitDurations(wpm, farnsworthWPM)
+	return t.duration(s, dit, spacingDit)
+}
+
+// DitDurationWithTiming returns the duration of the code with the given Timing,
+// relative to a Dit. See Code.DitDuration
+func (c Code) DitDurationWithTiming(t Timing) (d uint) {
+	for _, s := range c {
+		d += t.DitDuration(s)
+	}
+	return
+}
+
+// DurationWithTiming returns the duration of the code with the given Timing, at
+// the given WPM, e.g. to drive a sounder with American Morse:
+//
+//	FromTextWithDictionary("hello", American).DurationWithTiming(20, 0, AmericanTiming)
+//
+// See Code.Duration
+func (c Code) DurationWithTiming(wpm, farnsworthWPM uint, t Timing) (d time.Duration) {
+	dit, spacingDit := t.unitDurations(wpm, farnsworthWPM)
+	for _, s := range c {
+		d += t.duration(s, dit, spacingDit)
+	}
+	return d
+}
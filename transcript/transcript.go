@@ -0,0 +1,172 @@
+// Package transcript renders decoded Morse into messages, applying
+// the meaning of the prosigns in the text rather than writing them out
+package transcript
+
+import (
+	"bufio"
+	"github.com/bhollier/morse"
+	"github.com/bhollier/morse/prosign"
+	"io"
+	"strings"
+)
+
+// Message is a single message in a Transcript
+type Message struct {
+	// Paragraphs are the paragraphs of the message, each made up of words
+	Paragraphs [][]string
+	// Complete is whether the message was ended with <AR>
+	Complete bool
+}
+
+// String returns the text of the message,
+// with the paragraphs separated by blank lines
+func (m Message) String() string {
+	paragraphs := make([]string, 0, len(m.Paragraphs))
+	for _, p := range m.Paragraphs {
+		paragraphs = append(paragraphs, strings.Join(p, " "))
+	}
+	return strings.Join(paragraphs, "\n\n")
+}
+
+// empty returns whether the message has no words
+func (m Message) empty() bool {
+	return len(m.Paragraphs) == 0 || (len(m.Paragraphs) == 1 && len(m.Paragraphs[0]) == 0)
+}
+
+// Transcript is a list of messages
+type Transcript struct {
+	Messages []Message
+	// Whether the last message is still open (hasn't been ended with <AR>)
+	open bool
+}
+
+// String returns the text of the transcript, with the
+// messages separated by a line containing "---"
+func (t *Transcript) String() string {
+	messages := make([]string, 0, len(t.Messages))
+	for _, m := range t.Messages {
+		messages = append(messages, m.String())
+	}
+	return strings.Join(messages, "\n\n---\n\n")
+}
+
+// current returns the message currently being written, starting a new one if needed
+func (t *Transcript) current() *Message {
+	if !t.open {
+		t.Messages = append(t.Messages, Message{Paragraphs: [][]string{{}}})
+		t.open = true
+	}
+	return &t.Messages[len(t.Messages)-1]
+}
+
+// Add a token of decoded text to the transcript, which is either a word or a
+// prosign in the markup that morse.Decoder uses with prosign.Names, e.g. "<AR>".
+// The following prosigns are applied:
+//
+// - <HH> (prosign.Correction) erases the word before it
+//
+// - <BT> (prosign.Break) starts a new paragraph
+//
+// - <AR> (prosign.Out) ends the current message
+//
+// - <KA> (prosign.Attention) starts a new message
+//
+// Any other prosigns are kept as words
+func (t *Transcript) Add(token string) {
+	switch token {
+	case "<HH>":
+		t.erase()
+	case "<BT>":
+		m := t.current()
+		if len(m.Paragraphs[len(m.Paragraphs)-1]) > 0 {
+			m.Paragraphs = append(m.Paragraphs, []string{})
+		}
+	case "<AR>":
+		if t.open {
+			m := t.current()
+			m.Complete = true
+			// Remove a trailing empty paragraph
+			if len(m.Paragraphs) > 1 && len(m.Paragraphs[len(m.Paragraphs)-1]) == 0 {
+				m.Paragraphs = m.Paragraphs[:len(m.Paragraphs)-1]
+			}
+			t.open = false
+		}
+	case "<KA>":
+		if t.open && t.current().empty() {
+			// Already at the start of a message
+			return
+		}
+		t.open = false
+		t.current()
+	default:
+		m := t.current()
+		p := &m.Paragraphs[len(m.Paragraphs)-1]
+		*p = append(*p, token)
+	}
+}
+
+// erase removes the last word of the current message
+func (t *Transcript) erase() {
+	if !t.open {
+		return
+	}
+	m := t.current()
+	for i := len(m.Paragraphs) - 1; i >= 0; i-- {
+		if p := m.Paragraphs[i]; len(p) > 0 {
+			m.Paragraphs[i] = p[:len(p)-1]
+			return
+		}
+	}
+}
+
+// Read reads all the decoded text from r, which should be a morse.Decoder
+// that uses prosign.Names, and returns the Transcript of the text
+func Read(r io.Reader) (*Transcript, error) {
+	t := &Transcript{}
+	s := bufio.NewScanner(r)
+	s.Split(bufio.ScanWords)
+	for s.Scan() {
+		for _, token := range splitMarkup(s.Text()) {
+			t.Add(token)
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	// Remove a trailing empty message
+	if t.open && t.current().empty() {
+		t.Messages = t.Messages[:len(t.Messages)-1]
+		t.open = false
+	}
+	return t, nil
+}
+
+// FromCode decodes the given code with prosign.Names and the
+// morse.DefaultDictionary, and returns the Transcript of the text
+func FromCode(code morse.Code) *Transcript {
+	d := morse.NewDecoderWithOptions(morse.NewReader(code), morse.DecoderOptions{Prosigns: prosign.Names})
+	t, err := Read(d)
+	if err != nil {
+		// panic on error as neither CodeReader or Decoder should ever error
+		panic(err)
+	}
+	return t
+}
+
+// splitMarkup splits any prosign markup out of the word,
+// e.g. "TEST<AR>" into "TEST" and "<AR>"
+func splitMarkup(word string) (tokens []string) {
+	for len(word) > 0 {
+		start := strings.IndexRune(word, '<')
+		end := strings.IndexRune(word, '>')
+		if start == -1 || end < start {
+			return append(tokens, word)
+		}
+		if start > 0 {
+			tokens = append(tokens, word[:start])
+		}
+		tokens = append(tokens, word[start:end+1])
+		word = word[end+1:]
+	}
+	return
+}
@@ -0,0 +1,38 @@
+package transcript
+
+import (
+	"github.com/bhollier/morse"
+	"github.com/bhollier/morse/prosign"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestFromCode(t *testing.T) {
+	a := assert.New(t)
+
+	code := morse.JoinWords(
+		prosign.Attention,
+		morse.FromText("HELLO WORDL"), prosign.Correction, morse.FromText("WORLD"),
+		prosign.Break,
+		morse.FromText("SECOND PARAGRAPH"),
+		prosign.Out,
+		prosign.Attention,
+		morse.FromText("NEXT"),
+	)
+	tr := FromCode(code)
+	a.Equal([]Message{
+		{Paragraphs: [][]string{{"HELLO", "WORLD"}, {"SECOND", "PARAGRAPH"}}, Complete: true},
+		{Paragraphs: [][]string{{"NEXT"}}},
+	}, tr.Messages)
+	a.Equal("HELLO WORLD\n\nSECOND PARAGRAPH\n\n---\n\nNEXT", tr.String())
+
+	// Prosigns run together with the words, and other prosigns are kept
+	code = morse.JoinWords(
+		morse.JoinLetters(morse.FromText("TEST"), prosign.Out),
+		morse.FromText("MORE"), prosign.EndOfContact,
+	)
+	a.Equal([]Message{
+		{Paragraphs: [][]string{{"TEST"}}, Complete: true},
+		{Paragraphs: [][]string{{"MORE", "<SK>"}}},
+	}, FromCode(code).Messages)
+}
@@ -0,0 +1,145 @@
+package morse
+
+import (
+	"golang.org/x/text/unicode/norm"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Substitution records a rune that a TextEncoder couldn't encode directly,
+// and the text it was replaced with
+type Substitution struct {
+	// Rune is the rune from the input text
+	Rune rune
+	// Replacement is the text that was encoded instead, e.g. "E" for 'é' or
+	// "SS" for 'ß'. If the rune couldn't be transliterated, this is "?" with the
+	// SubstituteUnknown policy (even if EncoderOptions.Replacement is set)
+	// or empty with the SkipUnknown policy
+	Replacement string
+}
+
+// transliterations maps (lowercase) runes that don't decompose into a base letter
+// to the Latin text they are sent as, when the rune isn't in the Dictionary.
+// Guarded by transliterationsMu, see RegisterTransliteration
+var transliterations = map[rune]string{
+	'ß': "ss", 'ø': "o", 'æ': "ae", 'œ': "oe", 'þ': "th", 'ð': "d", 'ł': "l", 'đ': "d", 'ħ': "h",
+	'ı': "i", 'ŀ': "l", 'ŋ': "ng", 'ſ': "s", 'ĸ': "q",
+
+	// Cyrillic
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ж': "zh", 'з': "z", 'и': "i", 'й': "j",
+	'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u",
+	'ф': "f", 'х': "h", 'ц': "c", 'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "\"", 'ы': "y", 'ь': "'", 'э': "e",
+	'ю': "yu", 'я': "ya", 'і': "i", 'ї': "yi", 'є': "ye", 'ґ': "g", 'ђ': "dj", 'ј': "j", 'љ': "lj",
+	'њ': "nj", 'ћ': "c", 'џ': "dz",
+
+	// Greek
+	'α': "a", 'β': "v", 'γ': "g", 'δ': "d", 'ε': "e", 'ζ': "z", 'η': "i", 'θ': "th", 'ι': "i", 'κ': "k",
+	'λ': "l", 'μ': "m", 'ν': "n", 'ξ': "x", 'ο': "o", 'π': "p", 'ρ': "r", 'σ': "s", 'ς': "s", 'τ': "t",
+	'υ': "y", 'φ': "f", 'χ': "ch", 'ψ': "ps", 'ω': "o",
+
+	// Punctuation
+	'‘': "'", '’': "'", '‚': "'", '“': "\"", '”': "\"", '„': "\"", '«': "\"", '»': "\"",
+	'–': "-", '—': "-", '‐': "-", '…': "...", '¿': "?", '¡': "!",
+}
+
+var transliterationsMu sync.RWMutex
+
+// RegisterTransliteration adds (or replaces) the Latin text that the rune is sent
+// as by every TextEncoder, when the rune isn't in the Dictionary. Safe to call
+// while encoding. To only change one encoder, see EncoderOptions.Transliterations
+func RegisterTransliteration(r rune, text string) {
+	transliterationsMu.Lock()
+	defer transliterationsMu.Unlock()
+	transliterations[unicode.ToLower(r)] = text
+}
+
+// Transliteration returns the Latin text that the rune is sent as when
+// it isn't in the Dictionary, or false if it has no transliteration.
+// See RegisterTransliteration
+func Transliteration(r rune) (string, bool) {
+	transliterationsMu.RLock()
+	defer transliterationsMu.RUnlock()
+	text, ok := transliterations[unicode.ToLower(r)]
+	return text, ok
+}
+
+// stripDiacritics returns the rune with its diacritics removed, by decomposing
+// it (NFD) and removing the nonspacing marks, e.g. 'é' to "e".
+// Returns false if the rune has no diacritics
+func stripDiacritics(r rune) (string, bool) {
+	sb := strings.Builder{}
+	for _, d := range norm.NFD.String(string(r)) {
+		if !unicode.Is(unicode.Mn, d) {
+			sb.WriteRune(d)
+		}
+	}
+	if sb.Len() == 0 || sb.String() == string(r) {
+		return "", false
+	}
+	return sb.String(), true
+}
+
+// transliterate returns the text to send instead of the given rune, which is
+// the first of the following that the encoder can encode:
+//  1. The encoder's EncoderOptions.Transliterations
+//  2. The rune with its diacritics removed, e.g. 'é' to "e"
+//  3. The global transliterations, e.g. 'ß' to "ss" (see RegisterTransliteration)
+//  4. The global transliterations of the rune with its diacritics removed
+//
+// Returns false if none of these can be encoded
+func (e *TextEncoder) transliterate(r rune, shifts []Shift) (string, bool) {
+	r = unicode.ToLower(r)
+	candidates := make([]string, 0, 4)
+	if s, ok := e.opts.Transliterations[r]; ok {
+		candidates = append(candidates, s)
+	}
+	base, hasBase := stripDiacritics(r)
+	if hasBase {
+		candidates = append(candidates, base)
+	}
+	if s, ok := Transliteration(r); ok {
+		candidates = append(candidates, s)
+	}
+	if baseRunes := []rune(base); len(baseRunes) == 1 {
+		if s, ok := Transliteration(baseRunes[0]); ok {
+			candidates = append(candidates, s)
+		}
+	}
+	for _, s := range candidates {
+		if e.canEncode(s, shifts) {
+			return s, true
+		}
+	}
+	return "", false
+}
+
+// canEncode returns whether all the runes of s are in the encoder's
+// dictionaries, without changing the encoder's shift
+func (e *TextEncoder) canEncode(s string, shifts []Shift) bool {
+	dicts := e.dictionaries(shifts)
+	for rs := []rune(s); len(rs) > 0; {
+		n := 0
+		for _, d := range dicts {
+			if _, n = d.match(rs); n > 0 {
+				break
+			}
+		}
+		if n == 0 {
+			return false
+		}
+		rs = rs[n:]
+	}
+	return true
+}
+
+// Substitutions returns the runes that the encoder has replaced so far, in the
+// order they were read, because they aren't in the encoder's dictionaries
+func (e *TextEncoder) Substitutions() []Substitution {
+	return append([]Substitution(nil), e.substitutions...)
+}
+
+// substitute records that r was encoded as replacement
+func (e *TextEncoder) substitute(r rune, replacement string) {
+	e.substitutions = append(e.substitutions, Substitution{Rune: r, Replacement: strings.ToUpper(replacement)})
+}
@@ -0,0 +1,81 @@
+package morse
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestTextEncoder_Transliterate(t *testing.T) {
+	a := assert.New(t)
+
+	// Diacritics are removed if the letter isn't in the dictionary
+	e := ReaderFromText(strings.NewReader("Zoë"))
+	c, err := ReadAll(e)
+	a.NoError(err)
+	a.Equal(FromText("zoe").String(), Code(c).String())
+	a.Equal([]Substitution{{Rune: 'ë', Replacement: "E"}}, e.Substitutions())
+
+	// Letters in the dictionary are sent as-is
+	a.Equal(JoinLetters(FromCodeString("・－・－")).String(), FromText("ä").String())
+
+	a.Equal(FromText("moskva").String(), FromText("Москва").String())
+	a.Equal(FromText("athina").String(), FromText("Αθήνα").String())
+	a.Equal(FromText("strasse").String(), FromTextWithDictionary("Straße", Spanish).String())
+	a.Equal(FromText("ostergaard").String(), FromTextWithDictionary("Østergaard", Spanish).String())
+	a.Equal(FromText("no").String(), FromTextWithDictionary("nǿ", Spanish).String())
+
+	// Hard and soft signs are sent as quotation marks, rather than being dropped
+	e = ReaderFromText(strings.NewReader("объект мать"))
+	c, err = ReadAll(e)
+	a.NoError(err)
+	a.Equal(FromText("ob\"ekt mat'").String(), Code(c).String())
+	a.Contains(e.Substitutions(), Substitution{Rune: 'ъ', Replacement: "\""})
+	a.Contains(e.Substitutions(), Substitution{Rune: 'ь', Replacement: "'"})
+
+	// Custom transliterations take priority
+	e = ReaderFromTextWithOptions(strings.NewReader("5€ ë☃"), EncoderOptions{
+		Transliterations: map[rune]string{'€': "eur", 'ë': "e-"},
+	})
+	c, err = ReadAll(e)
+	a.NoError(err)
+	a.Equal(FromText("5eur e-?").String(), Code(c).String())
+	a.Equal([]Substitution{
+		{Rune: '€', Replacement: "EUR"},
+		{Rune: 'ë', Replacement: "E-"},
+		{Rune: '☃', Replacement: "?"},
+	}, e.Substitutions())
+}
+
+func TestRegisterTransliteration(t *testing.T) {
+	a := assert.New(t)
+
+	_, ok := Transliteration('☃')
+	a.False(ok)
+	RegisterTransliteration('☃', "snowman")
+	defer func() {
+		transliterationsMu.Lock()
+		delete(transliterations, '☃')
+		transliterationsMu.Unlock()
+	}()
+	text, ok := Transliteration('☃')
+	a.True(ok)
+	a.Equal("snowman", text)
+	a.Equal(FromText("snowman").String(), FromText("☃").String())
+
+	// Registering is safe while encoding
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 100; i++ {
+			RegisterTransliteration('☂', "umbrella")
+		}
+		close(done)
+	}()
+	for i := 0; i < 100; i++ {
+		FromText("☃ ☂")
+	}
+	<-done
+	transliterationsMu.Lock()
+	delete(transliterations, '☂')
+	transliterationsMu.Unlock()
+}
@@ -0,0 +1,226 @@
+package morse
+
+import (
+	"sort"
+	"strings"
+)
+
+// Tree is a dichotomic search tree of the codes in a Dictionary, where each
+// node is reached by walking the dit and dah branches from the root. It can be
+// walked one signal at a time with Step, e.g. to show what a character could
+// become while it's being keyed. Walking isn't safe for concurrent use, but
+// Lookup is. The tree is a snapshot, so it isn't updated if the Dictionary is
+type Tree struct {
+	root *treeNode
+	// The current node, or nil if the signals so far don't match any node
+	node *treeNode
+	// The signals stepped since the last Reset
+	code Code
+}
+
+type treeNode struct {
+	text string
+	ok   bool
+	dit  *treeNode
+	dah  *treeNode
+	// Any branches that aren't a dit or dah, e.g. American Morse's LongDah
+	other map[Signal]*treeNode
+}
+
+// Completion is a character that the code walked so far could become, see Tree.Completions
+type Completion struct {
+	Text string
+	// Code is the full code of the character
+	Code Code
+}
+
+// NewTree creates a Tree of the codes in the Dictionary that can be decoded
+// (not including its shifts)
+func NewTree(d *Dictionary) *Tree {
+	t := &Tree{root: &treeNode{}}
+	for _, entry := range d.Entries() {
+		if entry.EncodeOnly {
+			continue
+		}
+		n := t.root
+		for _, s := range entry.Code {
+			if s != SignalSpace {
+				n = n.childOrNew(s)
+			}
+		}
+		n.text = d.TextFromCode(entry.Code)
+		n.ok = true
+	}
+	t.node = t.root
+	return t
+}
+
+func (n *treeNode) child(s Signal) *treeNode {
+	switch s {
+	case Dit:
+		return n.dit
+	case Dah:
+		return n.dah
+	default:
+		return n.other[s]
+	}
+}
+
+func (n *treeNode) childOrNew(s Signal) *treeNode {
+	if c := n.child(s); c != nil {
+		return c
+	}
+	c := &treeNode{}
+	switch s {
+	case Dit:
+		n.dit = c
+	case Dah:
+		n.dah = c
+	default:
+		if n.other == nil {
+			n.other = make(map[Signal]*treeNode)
+		}
+		n.other[s] = c
+	}
+	return c
+}
+
+// children returns the branches of the node, dit then dah then any others
+func (n *treeNode) children() (signals []Signal, nodes []*treeNode) {
+	if n.dit != nil {
+		signals = append(signals, Dit)
+		nodes = append(nodes, n.dit)
+	}
+	if n.dah != nil {
+		signals = append(signals, Dah)
+		nodes = append(nodes, n.dah)
+	}
+	others := make([]Signal, 0, len(n.other))
+	for s := range n.other {
+		others = append(others, s)
+	}
+	sort.Slice(others, func(i, j int) bool { return others[i] < others[j] })
+	for _, s := range others {
+		signals = append(signals, s)
+		nodes = append(nodes, n.other[s])
+	}
+	return
+}
+
+// Step walks the tree by one signal, returning false if there is no character
+// that starts with the signals walked so far. SignalSpaces are ignored, and
+// a RuneSpace or WordSpace (the end of a character) resets to the root
+func (t *Tree) Step(s Signal) bool {
+	if s == SignalSpace {
+		return t.node != nil
+	}
+	if !s.Audible() && s.DitDuration() >= RuneSpace.DitDuration() {
+		t.Reset()
+		return true
+	}
+
+	if len(t.code) > 0 && t.code[len(t.code)-1].Audible() && s.Audible() {
+		t.code = append(t.code, SignalSpace)
+	}
+	t.code = append(t.code, s)
+	if t.node != nil {
+		t.node = t.node.child(s)
+	}
+	return t.node != nil
+}
+
+// Reset returns to the root of the tree
+func (t *Tree) Reset() {
+	t.node = t.root
+	t.code = t.code[:0]
+}
+
+// Code returns the signals walked since the last Reset
+func (t *Tree) Code() Code {
+	return append(Code(nil), t.code...)
+}
+
+// Candidate returns the text of the character of the signals walked so far,
+// or false if they aren't a character (although they may be the start of one)
+func (t *Tree) Candidate() (string, bool) {
+	if t.node == nil || !t.node.ok {
+		return "", false
+	}
+	return t.node.text, true
+}
+
+// Completions returns the characters that start with the signals walked so far
+// (not including the Candidate), shortest first, and dits before dahs
+func (t *Tree) Completions() []Completion {
+	if t.node == nil {
+		return nil
+	}
+	type queued struct {
+		node *treeNode
+		code Code
+	}
+	var completions []Completion
+	queue := []queued{{t.node, t.Code()}}
+	for len(queue) > 0 {
+		q := queue[0]
+		queue = queue[1:]
+		signals, nodes := q.node.children()
+		for i, child := range nodes {
+			code := append(Code(nil), q.code...)
+			if len(code) > 0 && code[len(code)-1].Audible() && signals[i].Audible() {
+				code = append(code, SignalSpace)
+			}
+			code = append(code, signals[i])
+			if child.ok {
+				completions = append(completions, Completion{Text: child.text, Code: code})
+			}
+			queue = append(queue, queued{child, code})
+		}
+	}
+	return completions
+}
+
+// Lookup returns the text of the given code (of a single character),
+// or false if it isn't in the tree. This doesn't affect Step
+func (t *Tree) Lookup(c Code) (string, bool) {
+	n := t.root
+	for _, s := range c {
+		if s == SignalSpace {
+			continue
+		}
+		if n = n.child(s); n == nil {
+			return "", false
+		}
+	}
+	return n.text, n.ok
+}
+
+// String returns the classic Morse tree, with a line for each node
+// indented by its depth, e.g.
+//
+//	・ E
+//	  ・ I
+//	    ・ S
+//
+// Nodes that aren't a character (but lead to one) are shown as "?"
+func (t *Tree) String() string {
+	sb := strings.Builder{}
+	var write func(n *treeNode, depth int)
+	write = func(n *treeNode, depth int) {
+		signals, nodes := n.children()
+		for i, child := range nodes {
+			sb.WriteString(strings.Repeat("  ", depth))
+			sb.WriteString(signals[i].String())
+			sb.WriteByte(' ')
+			if child.ok {
+				sb.WriteString(strings.ToUpper(child.text))
+			} else {
+				sb.WriteByte('?')
+			}
+			sb.WriteByte('\n')
+			write(child, depth+1)
+		}
+	}
+	write(t.root, 0)
+	return sb.String()
+}
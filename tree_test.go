@@ -0,0 +1,102 @@
+package morse
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestTree_Step(t *testing.T) {
+	a := assert.New(t)
+
+	d := NewDictionary()
+	addLetters(d)
+	tree := NewTree(d)
+
+	_, ok := tree.Candidate()
+	a.False(ok)
+	a.Len(tree.Completions(), 27)
+
+	a.True(tree.Step(Dah))
+	text, ok := tree.Candidate()
+	a.True(ok)
+	a.Equal("t", text)
+
+	a.True(tree.Step(SignalSpace))
+	a.True(tree.Step(Dah))
+	a.True(tree.Step(Dit))
+	text, _ = tree.Candidate()
+	a.Equal("g", text)
+	a.Equal(Code{Dah, SignalSpace, Dah, SignalSpace, Dit}, tree.Code())
+	a.Equal([]Completion{{Text: "z", Code: Z}, {Text: "q", Code: Q}}, tree.Completions())
+
+	// There's no letter starting with －－・－－
+	a.True(tree.Step(Dah))
+	a.False(tree.Step(Dah))
+	_, ok = tree.Candidate()
+	a.False(ok)
+	a.Empty(tree.Completions())
+	a.False(tree.Step(Dit))
+
+	// A rune space starts the next character
+	a.True(tree.Step(RuneSpace))
+	a.Empty(tree.Code())
+	a.True(tree.Step(Dit))
+	text, _ = tree.Candidate()
+	a.Equal("e", text)
+	a.Equal([]string{"i", "a", "s", "u", "r", "w", "h", "v", "f", "l", "p", "j", "é"}, completionTexts(tree.Completions()))
+}
+
+func completionTexts(completions []Completion) []string {
+	texts := make([]string, len(completions))
+	for i, c := range completions {
+		texts[i] = c.Text
+	}
+	return texts
+}
+
+func TestTree_Lookup(t *testing.T) {
+	a := assert.New(t)
+
+	tree := NewTree(DefaultDictionary)
+	for _, entry := range DefaultDictionary.Entries() {
+		if !entry.EncodeOnly {
+			text, ok := tree.Lookup(entry.Code)
+			a.True(ok, entry.Text)
+			a.Equal(entry.Text, text)
+		}
+	}
+	_, ok := tree.Lookup(JoinSignals(S, O, S))
+	a.False(ok)
+	_, ok = tree.Lookup(FromCodeString("－－－－"))
+	a.False(ok)
+
+	// Trees work with signals other than dits and dahs
+	text, ok := NewTree(American).Lookup(americanCode(".. ."))
+	a.True(ok)
+	a.Equal("c", text)
+}
+
+func TestTree_String(t *testing.T) {
+	a := assert.New(t)
+
+	d := NewDictionary()
+	d.Add('e', E)
+	d.Add('t', T)
+	d.Add('s', S)
+	d.Add('m', M)
+	a.Equal(strings.Join([]string{
+		"・ E",
+		"  ・ ?",
+		"    ・ S",
+		"－ T",
+		"  － M",
+	}, "\n")+"\n", NewTree(d).String())
+}
+
+func BenchmarkCodeLookupTree(b *testing.B) {
+	tree := NewTree(DefaultDictionary)
+	benchmarkCodeLookup(b, func(c Code) {
+		tree.Lookup(c)
+	})
+}
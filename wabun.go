@@ -0,0 +1,167 @@
+package morse
+
+import "strings"
+
+var (
+	// WabunIn is the DO prosign (－・・－－－), sent to shift from Latin into Wabun
+	WabunIn = FromCodeString("－・・－－－")
+	// WabunOut is the SN prosign (・・・－・), sent to shift from Wabun back into Latin
+	WabunOut = FromCodeString("・・・－・")
+)
+
+const (
+	// Dakuten is the voiced sound mark, sent after the kana it modifies
+	Dakuten = '゛'
+	// Handakuten is the semi-voiced sound mark, sent after the kana it modifies
+	Handakuten = '゜'
+)
+
+// Wabun is the Dictionary for Wabun code, the Japanese (katakana) variant of Morse.
+// It reuses many of the Latin codes, so it is added to DefaultDictionary as a Shift,
+// rather than directly. Hiragana is sent as its katakana equivalent, and kana with
+// a (han)dakuten are sent as the kana followed by the Dakuten or Handakuten sign
+var Wabun = NewDictionary()
+
+// wabunComposer is the Composer for Wabun, which splits and joins (han)dakuten
+type wabunComposer struct {
+	// Maps a kana with a (han)dakuten to the plain kana and the sign
+	decomposed map[rune][2]rune
+	// Maps a plain kana and a (han)dakuten sign to the combined kana
+	composed map[[2]rune]rune
+}
+
+func newWabunComposer() *wabunComposer {
+	c := &wabunComposer{
+		decomposed: make(map[rune][2]rune),
+		composed:   make(map[[2]rune]rune),
+	}
+	add := func(r, base, sign rune) {
+		c.decomposed[r] = [2]rune{base, sign}
+		c.composed[[2]rune{base, sign}] = r
+	}
+	// In the katakana block, the voiced form directly follows the plain kana,
+	// and the semi-voiced form follows that
+	for _, base := range "カキクケコサシスセソタチツテトハヒフヘホ" {
+		add(base+1, base, Dakuten)
+	}
+	for _, base := range "ハヒフヘホ" {
+		add(base+2, base, Handakuten)
+	}
+	add('ヴ', 'ウ', Dakuten)
+	return c
+}
+
+// Small kana aren't distinguished in Wabun, so are sent as the full size kana
+var wabunSmallKana = map[rune]rune{
+	'ァ': 'ア', 'ィ': 'イ', 'ゥ': 'ウ', 'ェ': 'エ', 'ォ': 'オ',
+	'ッ': 'ツ', 'ャ': 'ヤ', 'ュ': 'ユ', 'ョ': 'ヨ', 'ヮ': 'ワ',
+	'ヵ': 'カ', 'ヶ': 'ケ',
+}
+
+func (c *wabunComposer) Decompose(s string) string {
+	sb := strings.Builder{}
+	for _, r := range s {
+		// Convert hiragana to katakana
+		if r >= 'ぁ' && r <= 'ゖ' {
+			r += 'ァ' - 'ぁ'
+		}
+		// Convert the combining (han)dakuten into the standalone signs
+		switch r {
+		case '゙':
+			r = Dakuten
+		case '゚':
+			r = Handakuten
+		}
+		if full, ok := wabunSmallKana[r]; ok {
+			r = full
+		}
+		if d, ok := c.decomposed[r]; ok {
+			sb.WriteRune(d[0])
+			sb.WriteRune(d[1])
+		} else {
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+func (c *wabunComposer) Compose(s string) string {
+	rs := []rune(s)
+	sb := strings.Builder{}
+	for i := 0; i < len(rs); i++ {
+		if i+1 < len(rs) {
+			if r, ok := c.composed[[2]rune{rs[i], rs[i+1]}]; ok {
+				sb.WriteRune(r)
+				i++
+				continue
+			}
+		}
+		sb.WriteRune(rs[i])
+	}
+	return sb.String()
+}
+
+func init() {
+	// Wabun uses the same digits as International Morse, but its punctuation differs
+	addDigits(Wabun)
+	Wabun.AddCodeString('イ', "・－")
+	Wabun.AddCodeString('ロ', "・－・－")
+	Wabun.AddCodeString('ハ', "－・・・")
+	Wabun.AddCodeString('ニ', "－・－・")
+	Wabun.AddCodeString('ホ', "－・・")
+	Wabun.AddCodeString('ヘ', "・")
+	Wabun.AddCodeString('ト', "・・－・・")
+	Wabun.AddCodeString('チ', "・・－・")
+	Wabun.AddCodeString('リ', "－－・")
+	Wabun.AddCodeString('ヌ', "・・・・")
+	Wabun.AddCodeString('ル', "－・－－・")
+	Wabun.AddCodeString('ヲ', "・－－－")
+	Wabun.AddCodeString('ワ', "－・－")
+	Wabun.AddCodeString('カ', "・－・・")
+	Wabun.AddCodeString('ヨ', "－－")
+	Wabun.AddCodeString('タ', "－・")
+	Wabun.AddCodeString('レ', "－－－")
+	Wabun.AddCodeString('ソ', "－－－・")
+	Wabun.AddCodeString('ツ', "・－－・")
+	Wabun.AddCodeString('ネ', "－－・－")
+	Wabun.AddCodeString('ナ', "・－・")
+	Wabun.AddCodeString('ラ', "・・・")
+	Wabun.AddCodeString('ム', "－")
+	Wabun.AddCodeString('ウ', "・・－")
+	Wabun.AddCodeString('ヰ', "・－・・－")
+	Wabun.AddCodeString('ノ', "・・－－")
+	Wabun.AddCodeString('オ', "・－・・・")
+	Wabun.AddCodeString('ク', "・・・－")
+	Wabun.AddCodeString('ヤ', "・－－")
+	Wabun.AddCodeString('マ', "－・・－")
+	Wabun.AddCodeString('ケ', "－・－－")
+	Wabun.AddCodeString('フ', "－－・・")
+	Wabun.AddCodeString('コ', "－－－－")
+	Wabun.AddCodeString('エ', "－・－－－")
+	Wabun.AddCodeString('テ', "・－・－－")
+	Wabun.AddCodeString('ア', "－－・－－")
+	Wabun.AddCodeString('サ', "－・－・－")
+	Wabun.AddCodeString('キ', "－・－・・")
+	Wabun.AddCodeString('ユ', "－・・－－")
+	Wabun.AddCodeString('メ', "－・・・－")
+	Wabun.AddCodeString('ミ', "・・－・－")
+	Wabun.AddCodeString('シ', "－－・－・")
+	Wabun.AddCodeString('ヱ', "・－－・・")
+	Wabun.AddCodeString('ヒ', "－－・・－")
+	Wabun.AddCodeString('モ', "－・・－・")
+	Wabun.AddCodeString('セ', "・－－－・")
+	Wabun.AddCodeString('ス', "－－－・－")
+	Wabun.AddCodeString('ン', "・－・－・")
+	Wabun.AddCodeString(Dakuten, "・・")
+	Wabun.AddCodeString(Handakuten, "・・－－・")
+
+	Wabun.AddCodeString('ー', "・－－・－")
+	Wabun.AddCodeString('、', "・－・－・－")
+	Wabun.AddCodeString('」', "・－・－・・")
+	Wabun.AddCodeString('（', "－・－－・－")
+	Wabun.AddCodeString('）', "・－・・－・")
+
+	Wabun.SetComposer(newWabunComposer())
+
+	DefaultDictionary.AddShift(Shift{Dictionary: Wabun, In: WabunIn, Out: WabunOut})
+}
@@ -0,0 +1,38 @@
+package morse
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestWabun(t *testing.T) {
+	a := assert.New(t)
+
+	code := FromText("イロハ")
+	a.Equal(JoinLetters(WabunIn, A, FromCodeString("・－・－"), B).String(), code.String())
+	a.Equal("イロハ", Decode(code))
+
+	// Hiragana is sent as katakana
+	a.Equal(code.String(), FromText("いろは").String())
+
+	// Mixed Japanese and English should shift in and out
+	code = FromText("ABC イロハ DEF")
+	a.Equal(JoinWords(
+		JoinLetters(A, B, C),
+		JoinLetters(WabunIn, A, FromCodeString("・－・－"), B),
+		JoinLetters(WabunOut, D, E, F),
+	).String(), code.String())
+	a.Equal("ABC イロハ DEF", Decode(code))
+
+	code = FromText("SOSイロハ")
+	a.Equal("SOSイロハ", Decode(code))
+
+	// Dakuten and handakuten are sent as separate signs
+	code = FromText("ガパ")
+	a.Equal(JoinLetters(WabunIn, FromCodeString("・－・・"), FromCodeString("・・"),
+		FromCodeString("－・・・"), FromCodeString("・・－－・")).String(), code.String())
+	a.Equal("ガパ", Decode(code))
+
+	// Small kana are sent as the full size kana
+	a.Equal("ジヤパン", Decode(FromText("じゃぱん")))
+}
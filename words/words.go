@@ -35,6 +35,11 @@ func init() {
 	}
 }
 
+// All returns all the words
+func All() []string {
+	return append([]string(nil), words...)
+}
+
 // WithAnyRunes returns all words containing any of the given runes
 func WithAnyRunes(rs []rune) (words []string) {
 	wordSet := make(map[string]struct{})